@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/achievements"
+	"github.com/castrojo/contribemblem/internal/badge"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/emblem"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/history"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+// updateAchievements evaluates triumph rules against current stats and the
+// stats archive, records new unlocks and reports them
+func updateAchievements(cfg *config.Config, stats *github.Stats) error {
+	ws := workspace.New(cfg)
+	ledger, err := achievements.LoadLedger(ws.Ledger())
+	if err != nil {
+		return err
+	}
+	snapshots, err := history.List(ws.History())
+	if err != nil {
+		return err
+	}
+
+	unlocked := achievements.Evaluate(cfg.Achievements.Rules, stats, snapshots, ledger, time.Now())
+	if len(unlocked) == 0 {
+		return nil
+	}
+
+	if err := achievements.SaveLedger(ws.Ledger(), ledger); err != nil {
+		return err
+	}
+	for _, u := range unlocked {
+		fmt.Printf("🏆 Triumph unlocked: %s (%s)\n", u.Name, u.UnlockedAt)
+	}
+	return nil
+}
+
+// triumphs returns the most recent unlocks as badge icons
+func triumphs(cfg *config.Config) []badge.Triumph {
+	ledger, err := achievements.LoadLedger(workspace.New(cfg).Ledger())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read achievements: %v\n", err)
+		return nil
+	}
+
+	n := cfg.Achievements.MaxIcons
+	if n == 0 {
+		n = config.DefaultMaxTriumphIcons
+	}
+
+	var icons []badge.Triumph
+	for _, u := range ledger.Recent(n) {
+		icons = append(icons, badge.Triumph{Name: u.Name, Icon: u.Icon})
+	}
+	return icons
+}
+
+// emblemStats collects the Power Level and unlocked triumphs that gate
+// emblems.unlocks
+func emblemStats(cfg *config.Config, stats *github.Stats) (*emblem.Stats, error) {
+	progress := &emblem.Stats{
+		PowerLevel:   powerlevel.Calculate(powerlevel.FromStats(stats), &cfg.PowerLevel),
+		Achievements: map[string]bool{},
+	}
+	if !cfg.Achievements.Enabled {
+		return progress, nil
+	}
+
+	ledger, err := achievements.LoadLedger(workspace.New(cfg).Ledger())
+	if err != nil {
+		return nil, err
+	}
+	for id := range ledger {
+		progress.Achievements[id] = true
+	}
+	return progress, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/castrojo/contribemblem/internal/badge"
+	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/emblem"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+// generateAnimation writes the animated badge next to the static one when
+// badge.animation is enabled, returning its path ("" when disabled).
+// emblemRef is this week's emblem, already saved to the workspace.
+func generateAnimation(cfg *config.Config, stats *badge.Stats, opts badge.Options, emblemRef string) (string, error) {
+	if cfg == nil || !cfg.Badge.Animation.Enabled {
+		return "", nil
+	}
+
+	anim := &cfg.Badge.Animation
+	opts.Animation = &badge.Animation{
+		Format:   anim.Format,
+		Effects:  anim.EffectList(),
+		Frames:   anim.FrameCount(),
+		Duration: anim.LoopDuration(),
+	}
+	if slices.Contains(opts.Animation.Effects, config.EffectRotation) {
+		opts.Animation.Emblems = fetchRotationArt(cfg, emblemRef)
+	}
+
+	ws := workspace.New(cfg)
+	output := ws.Animation
+	if err := badge.GenerateWithOptions(ws.Emblem(), stats, output, opts); err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// fetchRotationArt downloads and decodes the rotation's other emblems in
+// order, starting after this week's. Emblems that can't be fetched are
+// skipped with a warning rather than failing the badge. The artwork only
+// lives in a temporary directory, so it never ends up in the profile repo.
+func fetchRotationArt(cfg *config.Config, current string) []image.Image {
+	dir, err := os.MkdirTemp("", "contribemblem-rotation-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to create rotation directory: %v\n", err)
+		return nil
+	}
+	defer os.RemoveAll(dir)
+
+	// The manifest is opened once, on the first Bungie emblem
+	var manifest *bungie.Manifest
+	var manifestErr error
+
+	rotation := cfg.Emblems.Rotation
+	start := slices.Index(rotation, current) + 1
+	var emblems []image.Image
+	for i := range rotation {
+		ref := rotation[(start+i)%len(rotation)]
+		if ref == current {
+			continue
+		}
+
+		path := filepath.Join(dir, fmt.Sprintf("%d.jpg", len(emblems)))
+		var err error
+		if emblem.IsCustom(ref) {
+			err = emblem.FetchCustom(ref, path)
+		} else {
+			if manifest == nil && manifestErr == nil {
+				manifest, manifestErr = openManifest(cfg)
+			}
+			err = manifestErr
+			var e bungie.Emblem
+			if err == nil {
+				e, err = manifest.Resolve(ref)
+			}
+			if err == nil {
+				err = bungie.DownloadEmblemArt(e, path)
+			}
+		}
+		var img image.Image
+		if err == nil {
+			img, err = badge.LoadEmblem(path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; %s left out of the animation\n", err, ref)
+			continue
+		}
+		emblems = append(emblems, img)
+	}
+	return emblems
+}
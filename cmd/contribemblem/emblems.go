@@ -0,0 +1,242 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/castrojo/contribemblem/internal/badge"
+	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+const (
+	defaultPreviewLimit = 12
+)
+
+// runEmblems implements `emblems search <text>`, `emblems list` and
+// `emblems preview` over the cached manifest
+func runEmblems(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: emblems search <text> | list [--source text] [--season hash] [--yaml] | preview [hash...] [--search text] [--out dir]")
+	}
+	sub, args := args[0], args[1:]
+
+	fs := flag.NewFlagSet("emblems "+sub, flag.ContinueOnError)
+	source := fs.String("source", "", "only emblems whose source contains this text")
+	season := fs.Uint("season", 0, "only emblems from this season hash")
+	asYAML := fs.Bool("yaml", false, "print an emblems.rotation block instead")
+	search := fs.String("search", "", "preview: emblems matching this text instead of hashes")
+	outDir := fs.String("out", workspace.New(cfg).Preview(), "preview: output directory")
+	limit := fs.Int("limit", defaultPreviewLimit, "preview: maximum emblems from a search")
+
+	// Allow flags before or after the positional arguments
+	var terms []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		terms = append(terms, args[0])
+		args = args[1:]
+	}
+	if *limit < 1 {
+		return fmt.Errorf("--limit must be at least 1")
+	}
+
+	filter := bungie.EmblemFilter{Source: *source, SeasonHash: uint32(*season)}
+	switch sub {
+	case "search":
+		if len(terms) == 0 {
+			return fmt.Errorf("usage: emblems search <text>")
+		}
+		filter.Query = strings.Join(terms, " ")
+	case "list":
+		if len(terms) > 0 {
+			return fmt.Errorf("emblems list takes no arguments (use emblems search)")
+		}
+	case "preview":
+		filter.Query = *search
+		if len(terms) == 0 && filter == (bungie.EmblemFilter{}) {
+			return fmt.Errorf("usage: emblems preview <hash...> (or --search/--source/--season)")
+		}
+	default:
+		return fmt.Errorf("unknown emblems command %q (want search, list or preview)", sub)
+	}
+
+	manifest, err := bungie.OpenManifest(workspace.New(cfg).Manifest())
+	if err != nil {
+		return err
+	}
+
+	var matches []bungie.Emblem
+	if sub == "preview" && len(terms) > 0 {
+		// Same resolution as the rotation, so ambiguous names are errors
+		for _, ref := range terms {
+			e, err := manifest.Resolve(ref)
+			if err != nil {
+				return err
+			}
+			matches = append(matches, e)
+		}
+	} else {
+		matches = bungie.FilterEmblems(manifest.Emblems(), filter)
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no emblems match")
+	}
+
+	if sub == "preview" {
+		if len(terms) == 0 && len(matches) > *limit {
+			fmt.Fprintf(os.Stderr, "Note: %d emblems match, previewing the first %d (see --limit)\n", len(matches), *limit)
+			matches = matches[:*limit]
+		}
+		return previewEmblems(cfg, matches, *outDir)
+	}
+
+	if *asYAML {
+		fmt.Print(bungie.RotationYAML(matches))
+		return nil
+	}
+	for _, e := range matches {
+		printEmblem(e)
+	}
+	fmt.Printf("%d emblem(s)\n", len(matches))
+	return nil
+}
+
+// resolveEmblem turns a rotation entry (hash or name) into a manifest
+// emblem. Hashes still resolve without a manifest, just without a name.
+func resolveEmblem(cfg *config.Config, ref string) (bungie.Emblem, error) {
+	manifest := workspace.New(cfg).Manifest()
+	if err := bungie.EnsureManifest(manifest); err != nil {
+		if bungie.IsHash(ref) {
+			fmt.Fprintf(os.Stderr, "Warning: %v; emblem name unavailable\n", err)
+			return bungie.Emblem{Hash: ref}, nil
+		}
+		return bungie.Emblem{}, fmt.Errorf("resolving emblem name %q: %w", ref, err)
+	}
+	return bungie.ResolveEmblem(manifest, ref)
+}
+
+// openManifest makes sure the manifest is cached and parses it, for
+// resolving several emblems at once
+func openManifest(cfg *config.Config) (*bungie.Manifest, error) {
+	manifest := workspace.New(cfg).Manifest()
+	if err := bungie.EnsureManifest(manifest); err != nil {
+		return nil, err
+	}
+	return bungie.OpenManifest(manifest)
+}
+
+// emblemRefHash accepts a hash, a name, or select-emblem's "hash  name" line
+func emblemRefHash(cfg *config.Config, ref string) (string, error) {
+	if fields := strings.Fields(ref); len(fields) > 0 && bungie.IsHash(fields[0]) {
+		return fields[0], nil
+	}
+	e, err := resolveEmblem(cfg, ref)
+	if err != nil {
+		return "", err
+	}
+	return e.Hash, nil
+}
+
+// saveEmblemRef records the emblem entry just fetched, so generate can
+// apply its crop offset without re-running selection. Bungie refs are
+// stored as the configured entry when one matches, otherwise as the hash.
+func saveEmblemRef(cfg *config.Config, ref, hash string) error {
+	saved := ref
+	if hash != "" {
+		saved = hash
+		if cfg != nil {
+			for _, entry := range cfg.Emblems.Hashes() {
+				if entry == ref || entry == hash {
+					saved = entry
+					break
+				}
+			}
+		}
+	}
+	path := workspace.New(cfg).EmblemRef()
+	if err := os.WriteFile(path, []byte(saved+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to record emblem: %w", err)
+	}
+	return nil
+}
+
+// fetchedEmblemRef reads the entry recorded by saveEmblemRef, or "" when
+// the emblem on disk predates it
+func fetchedEmblemRef(cfg *config.Config) string {
+	data, err := os.ReadFile(workspace.New(cfg).EmblemRef())
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// previewEmblems downloads each emblem's art and renders a contact sheet of
+// full badges with the current stats
+func previewEmblems(cfg *config.Config, emblems []bungie.Emblem, dir string) error {
+	ghStats, err := loadStats(workspace.New(cfg).Stats())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v; previewing with empty stats\n", err)
+		ghStats = &github.Stats{}
+	}
+
+	artDir := filepath.Join(dir, "art")
+	if err := os.MkdirAll(artDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", artDir, err)
+	}
+
+	var entries []badge.SheetEntry
+	for _, e := range emblems {
+		artPath := filepath.Join(artDir, e.Hash+".jpg")
+		fmt.Fprintf(os.Stderr, "Downloading %s (%s)...\n", e.Hash, e.Name)
+		if err := bungie.DownloadEmblemArt(e, artPath); err != nil {
+			return err
+		}
+		entries = append(entries, badge.SheetEntry{
+			Hash:       e.Hash,
+			Name:       e.Name,
+			EmblemPath: artPath,
+			CropOffset: cropOffset(cfg, e.Hash),
+		})
+	}
+
+	if err := badge.GenerateContactSheet(entries, newBadgeStats(cfg, ghStats), badgeOptions(cfg), dir); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Preview written to %s (contact-sheet.png, index.html)\n", dir)
+	return nil
+}
+
+// printEmblem prints one emblem as a short block
+func printEmblem(e bungie.Emblem) {
+	fmt.Printf("%s  %s\n", e.Hash, e.Name)
+	if e.Description != "" {
+		fmt.Printf("    %s\n", strings.ReplaceAll(e.Description, "\n", " "))
+	}
+	if e.Source != "" {
+		fmt.Printf("    %s\n", e.Source)
+	}
+	if e.SeasonHash != 0 {
+		fmt.Printf("    season:  %d\n", e.SeasonHash)
+	}
+	for _, img := range []struct{ label, url string }{
+		{"icon:   ", e.Icon},
+		{"banner: ", e.SecondaryIcon},
+		{"special:", e.SecondarySpecial},
+	} {
+		if img.url != "" {
+			fmt.Printf("    %s %s\n", img.label, img.url)
+		}
+	}
+	fmt.Println()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+func TestRunEmblemsRejectsLimit(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputConfig{DataDir: t.TempDir()}}
+	for _, limit := range []string{"0", "-1"} {
+		err := runEmblems(cfg, []string{"preview", "--search", "Alph", "--limit", limit})
+		if err == nil || !strings.Contains(err.Error(), "--limit must be at least 1") {
+			t.Errorf("--limit %s: expected usage error, got %v", limit, err)
+		}
+	}
+}
+
+func TestRunEmblemsPreviewAmbiguousName(t *testing.T) {
+	cfg := &config.Config{Output: config.OutputConfig{DataDir: t.TempDir()}}
+	manifest := `{
+  "1": {"displayProperties": {"name": "Twin"}, "itemType": 14},
+  "2": {"displayProperties": {"name": "Twin"}, "itemType": 14}
+}`
+	if err := os.WriteFile(workspace.New(cfg).Manifest(), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write test manifest: %v", err)
+	}
+
+	err := runEmblems(cfg, []string{"preview", "twin"})
+	if err == nil || !strings.Contains(err.Error(), "use a hash: 1, 2") {
+		t.Errorf("expected ambiguity error listing hashes, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/history"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+// runHistory implements `history list` and `history diff [from] [to]`
+func runHistory(cfg *config.Config, args []string) error {
+	sub := "list"
+	if len(args) > 0 {
+		sub = args[0]
+	}
+
+	dir := workspace.New(cfg).History()
+	snapshots, err := history.List(dir)
+	if err != nil {
+		return err
+	}
+
+	var plConfig *config.PowerLevelConfig
+	if cfg != nil {
+		plConfig = &cfg.PowerLevel
+	}
+
+	switch sub {
+	case "list":
+		if len(snapshots) == 0 {
+			fmt.Printf("No snapshots in %s\n", dir)
+			return nil
+		}
+		fmt.Printf("%-10s %8s %8s %6s %6s %8s %6s\n", "WEEK", "POWER", "COMMITS", "PRS", "ISSUES", "REVIEWS", "STARS")
+		for _, snap := range snapshots {
+			s := snap.Stats
+			level := powerlevel.Calculate(powerlevel.FromStats(s), plConfig)
+			fmt.Printf("%-10s %8d %8d %6d %6d %8d %6d\n", snap.Week, level, s.Commits, s.PullRequests, s.Issues, s.Reviews, s.StarsReceived)
+		}
+		return nil
+	case "diff":
+		from, to, err := diffTargets(dir, snapshots, args[1:])
+		if err != nil {
+			return err
+		}
+		deltas := history.Deltas(from.Stats, to.Stats)
+		if deltas == nil {
+			return fmt.Errorf("%s and %s are from different years; stats reset every January", from.Week, to.Week)
+		}
+
+		fmt.Printf("%s → %s\n", from.Week, to.Week)
+		before, after := powerlevel.FromStats(from.Stats), powerlevel.FromStats(to.Stats)
+		for _, key := range config.AllMetrics {
+			fmt.Printf("  %-26s %10g → %10g  (%+g)\n", key, before[key], after[key], deltas[key])
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown history command %q (want list or diff)", sub)
+	}
+}
+
+// diffTargets resolves the snapshots to compare: explicit weeks, one week
+// against the latest, or the two most recent snapshots
+func diffTargets(dir string, snapshots []history.Snapshot, weeks []string) (*history.Snapshot, *history.Snapshot, error) {
+	switch len(weeks) {
+	case 0:
+		if len(snapshots) < 2 {
+			return nil, nil, fmt.Errorf("need at least two snapshots to diff, have %d", len(snapshots))
+		}
+		return &snapshots[len(snapshots)-2], &snapshots[len(snapshots)-1], nil
+	case 1:
+		if len(snapshots) == 0 {
+			return nil, nil, fmt.Errorf("no snapshots in %s", dir)
+		}
+		from, err := history.Load(dir, weeks[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		return from, &snapshots[len(snapshots)-1], nil
+	default:
+		from, err := history.Load(dir, weeks[0])
+		if err != nil {
+			return nil, nil, err
+		}
+		to, err := history.Load(dir, weeks[1])
+		if err != nil {
+			return nil, nil, err
+		}
+		return from, to, nil
+	}
+}
+
+// weeklyDeltas compares stats against the latest archived snapshot from an
+// earlier week. Missing history yields no deltas rather than an error.
+func weeklyDeltas(cfg *config.Config, stats *github.Stats) map[string]float64 {
+	week := history.StatsWeek(stats, time.Now())
+	prev, err := history.Previous(workspace.New(cfg).History(), week)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read stats history: %v\n", err)
+		return nil
+	}
+	if prev == nil {
+		return nil
+	}
+	return history.Deltas(prev.Stats, stats)
+}
+
+// sparklineSeries returns the last N weeks of activity for the badge
+// sparkline, from the contribution calendar or archived Power Levels
+func sparklineSeries(cfg *config.Config, stats *github.Stats) []float64 {
+	weeks := cfg.Badge.Sparkline.Weeks
+	if weeks == 0 {
+		weeks = config.DefaultSparklineWeeks
+	}
+
+	var series []float64
+	if cfg.Badge.Sparkline.Source == config.SparklineHistory {
+		snapshots, err := history.List(workspace.New(cfg).History())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read stats history: %v\n", err)
+			return nil
+		}
+		for _, snap := range snapshots {
+			series = append(series, float64(powerlevel.Calculate(powerlevel.FromStats(snap.Stats), &cfg.PowerLevel)))
+		}
+	} else {
+		for _, count := range stats.WeeklyContributions {
+			series = append(series, float64(count))
+		}
+	}
+
+	if len(series) > weeks {
+		series = series[len(series)-weeks:]
+	}
+	return series
+}
@@ -1,39 +1,78 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/castrojo/contribemblem/internal/badge"
 	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/cache"
 	"github.com/castrojo/contribemblem/internal/config"
 	"github.com/castrojo/contribemblem/internal/emblem"
 	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/history"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
 	"github.com/castrojo/contribemblem/internal/readme"
+	"github.com/castrojo/contribemblem/internal/workspace"
 )
 
 func main() {
-	if len(os.Args) < 2 {
+	global := flag.NewFlagSet("contribemblem", flag.ContinueOnError)
+	global.Usage = printUsage
+	configPath := global.String("config", config.DefaultConfigPath, "config file, relative to --workdir")
+	workdir := global.String("workdir", "", "profile repository to run in (default: current directory)")
+	if err := global.Parse(os.Args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			os.Exit(0)
+		}
+		os.Exit(2)
+	}
+	args := global.Args()
+	if len(args) < 1 {
 		printUsage()
 		os.Exit(1)
 	}
 
+	// Like git -C: every relative path, file: emblems included, resolves
+	// against the profile repository
+	if *workdir != "" {
+		if err := os.Chdir(*workdir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Load config from contribemblem.yml (fallback to env vars if not found)
-	cfg, err := config.Load(config.DefaultConfigPath)
+	cfg, err := config.Load(*configPath)
 	if err != nil {
+		// An explicitly requested config has to load
+		explicit := false
+		global.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "config" })
+		if explicit && args[0] != "validate" {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		// Config file not found or invalid - this is OK for backwards compatibility
 		// Commands will fall back to environment variables (GITHUB_ACTOR)
 		fmt.Fprintf(os.Stderr, "Note: Config file not loaded (%v), using environment variables\n", err)
 		cfg = nil
 	}
+	ws := workspace.New(cfg)
 
-	cmd := os.Args[1]
+	cmd := args[0]
 	switch cmd {
 	case "fetch-stats":
-		username := getUsername(cfg)
-		stats, err := github.FetchStats(username, nil)
+		fs := flag.NewFlagSet("fetch-stats", flag.ContinueOnError)
+		forceRefresh := fs.Bool("force-refresh", false, "refetch even if cached stats are fresh")
+		if err := fs.Parse(args[1:]); err != nil {
+			os.Exit(2)
+		}
+		stats, _, err := fetchStats(cfg, *forceRefresh)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
@@ -41,66 +80,158 @@ func main() {
 		data, _ := json.MarshalIndent(stats, "", "  ")
 		fmt.Println(string(data))
 	case "select-emblem":
-		// Try config first, fall back to JSON file
-		var selectedEmblem string
-		if cfg != nil {
-			selectedEmblem, err = emblem.SelectEmblemFromConfig(&cfg.Emblems)
-		} else {
-			selectedEmblem, err = emblem.SelectEmblem(emblem.DefaultConfigPath)
+		// Unlock gating needs stats; use the last run's if available
+		var stats *github.Stats
+		if cfg != nil && len(cfg.Emblems.Unlocks) > 0 {
+			if stats, err = loadStats(ws.Stats()); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v; gated emblems stay locked\n", err)
+			}
 		}
+		selectedEmblem, err := selectEmblem(cfg, stats)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println(selectedEmblem)
+		// Custom artwork has no hash or name to resolve
+		var selected bungie.Emblem
+		if !emblem.IsCustom(selectedEmblem) {
+			if selected, err = resolveEmblem(cfg, selectedEmblem); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if len(args) > 1 && args[1] == "--json" {
+			data, _ := json.Marshal(struct {
+				Hash   string `json:"hash,omitempty"`
+				Name   string `json:"name,omitempty"`
+				Custom string `json:"custom,omitempty"`
+			}{selected.Hash, selected.Name, customRef(selectedEmblem)})
+			fmt.Println(string(data))
+		} else if emblem.IsCustom(selectedEmblem) {
+			fmt.Println(selectedEmblem)
+		} else {
+			// Hash first so `select-emblem | fetch-emblem` keeps working
+			fmt.Println(strings.TrimSpace(selected.Hash + "  " + selected.Name))
+		}
 	case "fetch-emblem":
-		// Read emblem hash from args or stdin
-		var emblemHash string
-		if len(os.Args) > 2 {
-			emblemHash = os.Args[2]
+		// Read emblem hash or name from args or stdin
+		var ref string
+		if len(args) > 1 {
+			ref = strings.Join(args[1:], " ")
 		} else {
-			if _, err := fmt.Scanln(&emblemHash); err != nil {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
 				fmt.Fprintf(os.Stderr, "Error reading emblem hash: %v\n", err)
 				os.Exit(1)
 			}
+			ref = strings.TrimSpace(line)
 		}
-		if err := bungie.FetchEmblem(emblemHash); err != nil {
+		if emblem.IsCustom(ref) {
+			if err := emblem.FetchCustom(ref, ws.Emblem()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := saveEmblemRef(cfg, ref, ""); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "✓ Custom emblem saved to %s\n", ws.Emblem())
+			break
+		}
+		emblemHash, err := emblemRefHash(cfg, ref)
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-	case "generate":
-		// Read stats from data/stats.json
-		statsData, err := os.ReadFile("data/stats.json")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading stats.json: %v\n", err)
+		if err := bungie.FetchEmblem(ws.Manifest(), emblemHash, ws.Emblem()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-
-		var ghStats github.Stats
-		if err := json.Unmarshal(statsData, &ghStats); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing stats.json: %v\n", err)
+		if err := saveEmblemRef(cfg, ref, emblemHash); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "generate":
+		// Read the cached stats, warning if they look outdated
+		ghStats, err := loadCachedStats(cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		// Convert to badge.Stats
-		badgeStats := &badge.Stats{
-			Username:     getUsername(cfg),
-			Commits:      ghStats.Commits,
-			PullRequests: ghStats.PullRequests,
-			Issues:       ghStats.Issues,
-			Reviews:      ghStats.Reviews,
-			Stars:        ghStats.StarsReceived,
-		}
+		badgeStats := newBadgeStats(cfg, ghStats)
+
+		// Per-emblem crop offsets and the animation's rotation start from
+		// the emblem fetch-emblem or run last saved
+		opts := badgeOptions(cfg)
+		emblemRef := fetchedEmblemRef(cfg)
+		opts.CropOffset = cropOffset(cfg, emblemRef)
 
 		// Generate badge
-		if err := badge.Generate("data/emblem.jpg", badgeStats, "badge.png"); err != nil {
+		output := ws.Badge
+		if err := badge.GenerateWithOptions(ws.Emblem(), badgeStats, output, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Error generating badge: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Println("✓ Badge generated: badge.png")
+		fmt.Printf("✓ Badge generated: %s (%s)\n", output, fileSize(output))
+
+		animated, err := generateAnimation(cfg, badgeStats, opts, emblemRef)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating animated badge: %v\n", err)
+			os.Exit(1)
+		}
+		if animated != "" {
+			fmt.Printf("✓ Animated badge generated: %s (%s)\n", animated, fileSize(animated))
+		}
+	case "power-level":
+		// Compute Power Level from the cached stats using the configured formula
+		ghStats, err := loadStats(ws.Stats())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var plCfg *config.PowerLevelConfig
+		if cfg != nil {
+			plCfg = &cfg.PowerLevel
+		}
+		result := powerlevel.Compute(powerlevel.FromStats(ghStats), plCfg)
+
+		if len(args) > 1 && args[1] == "--breakdown" {
+			for _, c := range result.Contributions {
+				fmt.Printf("%-26s %10.2f → %8.1f\n", c.Metric, c.Value, c.Points)
+			}
+			fmt.Printf("%-26s %21.1f\n", "raw total", result.Raw)
+		}
+		fmt.Println(result.Level)
+	case "emblems":
+		if err := runEmblems(cfg, args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "validate":
+		// Re-load to report why the config was rejected
+		if cfg == nil {
+			if _, err := config.Load(*configPath); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ %v\n", err)
+				os.Exit(1)
+			}
+		}
+		fmt.Printf("✓ %s is valid\n", *configPath)
+		if err := validateEmblems(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "history":
+		if err := runHistory(cfg, args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	case "update-readme":
-		changed, err := readme.Inject("README.md", "badge.png", time.Now())
+		changed, err := readme.Inject(ws.Readme, ws.BadgeLink(), time.Now())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error updating README: %v\n", err)
 			os.Exit(1)
@@ -111,70 +242,120 @@ func main() {
 			fmt.Println("✓ README already current")
 		}
 	case "run":
+		fs := flag.NewFlagSet("run", flag.ContinueOnError)
+		strict := fs.Bool("strict", false, "validate every configured emblem first")
+		forceRefresh := fs.Bool("force-refresh", false, "refetch stats even if cached ones are fresh")
+		if err := fs.Parse(args[1:]); err != nil {
+			os.Exit(2)
+		}
+
 		fmt.Println("Running full ContribEmblem pipeline...")
 
-		// Step 1: Fetch GitHub stats
+		// --strict fails fast if any configured emblem is unusable
+		if *strict {
+			if cfg == nil {
+				fmt.Fprintf(os.Stderr, "Strict mode: --strict requires a config file (%s not loaded)\n", *configPath)
+				os.Exit(1)
+			}
+			if err := validateEmblems(cfg); err != nil {
+				fmt.Fprintf(os.Stderr, "Strict mode: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Step 1: Fetch GitHub stats, reusing the cached ones while fresh
 		fmt.Println("[1/5] Fetching GitHub stats...")
-		username := getUsername(cfg)
-		stats, err := github.FetchStats(username, nil)
+		stats, cached, err := fetchStats(cfg, *forceRefresh)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to fetch stats: %v\n", err)
 			os.Exit(1)
 		}
+		if cached {
+			fmt.Printf("✓ Reusing stats from %s\n", ws.Stats())
+		} else {
+			fmt.Printf("✓ Stats saved to %s\n", ws.Stats())
+		}
 
-		// Save stats to data/stats.json
-		if err := os.MkdirAll("data", 0755); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to create data directory: %v\n", err)
+		// Archive this week's snapshot before anything can overwrite it
+		snapshotPath, err := history.Save(ws.History(), stats, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to archive stats: %v\n", err)
 			os.Exit(1)
 		}
-		statsJSON, _ := json.MarshalIndent(stats, "", "  ")
-		if err := os.WriteFile("data/stats.json", statsJSON, 0644); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to write stats.json: %v\n", err)
-			os.Exit(1)
+		fmt.Printf("✓ Stats archived to %s\n", snapshotPath)
+
+		if cfg != nil && cfg.Achievements.Enabled {
+			if err := updateAchievements(cfg, stats); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to update achievements: %v\n", err)
+				os.Exit(1)
+			}
 		}
-		fmt.Println("✓ Stats saved to data/stats.json")
 
 		// Step 2: Select emblem
 		fmt.Println("[2/5] Selecting weekly emblem...")
-		var emblemHash string
-		if cfg != nil {
-			emblemHash, err = emblem.SelectEmblemFromConfig(&cfg.Emblems)
-		} else {
-			emblemHash, err = emblem.SelectEmblem(emblem.DefaultConfigPath)
-		}
+		emblemRef, err := selectEmblem(cfg, stats)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to select emblem: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Printf("✓ Selected emblem: %s\n", emblemHash)
+		if emblem.IsCustom(emblemRef) {
+			fmt.Printf("✓ Selected custom emblem: %s\n", emblemRef)
 
-		// Step 3: Fetch emblem from Bungie
-		fmt.Println("[3/5] Fetching emblem from Bungie API...")
-		if err := bungie.FetchEmblem(emblemHash); err != nil {
+			// Step 3: Custom artwork bypasses Bungie entirely
+			fmt.Println("[3/5] Fetching custom emblem artwork...")
+			if err := emblem.FetchCustom(emblemRef, ws.Emblem()); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to fetch emblem: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			selected, err := resolveEmblem(cfg, emblemRef)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to resolve emblem: %v\n", err)
+				os.Exit(1)
+			}
+			if selected.Name != "" {
+				fmt.Printf("✓ Selected emblem: %s (%s)\n", selected.Hash, selected.Name)
+			} else {
+				fmt.Printf("✓ Selected emblem: %s\n", selected.Hash)
+			}
+
+			// Step 3: Fetch emblem from Bungie
+			fmt.Println("[3/5] Fetching emblem from Bungie API...")
+			if err := bungie.FetchEmblem(ws.Manifest(), selected.Hash, ws.Emblem()); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to fetch emblem: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := saveEmblemRef(cfg, emblemRef, ""); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to fetch emblem: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("✓ Emblem downloaded to data/emblem.jpg")
+		fmt.Printf("✓ Emblem downloaded to %s\n", ws.Emblem())
 
 		// Step 4: Generate badge
 		fmt.Println("[4/5] Generating badge image...")
-		badgeStats := &badge.Stats{
-			Username:     getUsername(cfg),
-			Commits:      stats.Commits,
-			PullRequests: stats.PullRequests,
-			Issues:       stats.Issues,
-			Reviews:      stats.Reviews,
-			Stars:        stats.StarsReceived,
-		}
-		if err := badge.Generate("data/emblem.jpg", badgeStats, "badge.png"); err != nil {
+		badgeStats := newBadgeStats(cfg, stats)
+		opts := badgeOptions(cfg)
+		opts.CropOffset = cropOffset(cfg, emblemRef)
+		output := ws.Badge
+		if err := badge.GenerateWithOptions(ws.Emblem(), badgeStats, output, opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to generate badge: %v\n", err)
 			os.Exit(1)
 		}
-		fmt.Println("✓ Badge generated: badge.png")
+		fmt.Printf("✓ Badge generated: %s (%s)\n", output, fileSize(output))
+
+		animated, err := generateAnimation(cfg, badgeStats, opts, emblemRef)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to generate animated badge: %v\n", err)
+			os.Exit(1)
+		}
+		if animated != "" {
+			fmt.Printf("✓ Animated badge generated: %s (%s)\n", animated, fileSize(animated))
+		}
 
 		// Step 5: Update README
 		fmt.Println("[5/5] Updating README...")
-		changed, err := readme.Inject("README.md", "badge.png", time.Now())
+		changed, err := readme.Inject(ws.Readme, ws.BadgeLink(), time.Now())
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to update README: %v\n", err)
 			os.Exit(1)
@@ -185,9 +366,30 @@ func main() {
 			fmt.Println("✓ README already current")
 		}
 
-		fmt.Println("\n🎉 Pipeline complete! Badge ready at badge.png")
+		fmt.Printf("\n🎉 Pipeline complete! Badge ready at %s\n", output)
+	case "serve":
+		if err := runServe(cfg, *configPath, args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case "paths":
+		// name=path lines for $GITHUB_OUTPUT, so workflows cache and stage
+		// the configured workspace rather than fixed names
+		fmt.Printf("badge=%s\n", ws.Badge)
+		if cfg != nil && cfg.Badge.Animation.Enabled {
+			fmt.Printf("animation=%s\n", ws.Animation)
+		}
+		fmt.Printf("readme=%s\n", ws.Readme)
+		fmt.Printf("data_dir=%s\n", ws.DataDir)
+		fmt.Printf("stats=%s\n", ws.Stats())
+		fmt.Printf("stats_meta=%s\n", cache.MetaPath(ws.Stats()))
+		fmt.Printf("manifest=%s\n", ws.Manifest())
+		fmt.Printf("emblem_config=%s\n", ws.EmblemConfig())
+		if cfg != nil {
+			fmt.Printf("config=%s\n", *configPath)
+		}
 	case "generate-demos":
-		if err := generateDemos(); err != nil {
+		if err := generateDemos(ws); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -201,6 +403,106 @@ func main() {
 	}
 }
 
+// loadStats reads fetched GitHub stats from a stats.json file
+func loadStats(path string) (*github.Stats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var stats github.Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &stats, nil
+}
+
+// badgeOptions builds rendering options from config (defaults when cfg is nil)
+func badgeOptions(cfg *config.Config) badge.Options {
+	var opts badge.Options
+	if cfg != nil {
+		opts.PowerLevel = &cfg.PowerLevel
+		opts.Crop = cfg.Badge.Crop
+		opts.Encoding = badge.Encoding{
+			Compression: badge.CompressionLevel(cfg.Output.Compression),
+			Colors:      cfg.Output.Colors,
+			Quality:     cfg.Output.Quality,
+		}
+		opts.Palette = cfg.Badge.Palette
+		if cfg.Badge.AdaptiveContrast {
+			opts.ContrastTarget = cfg.Badge.ContrastTarget
+			if opts.ContrastTarget == 0 {
+				opts.ContrastTarget = badge.DefaultContrastTarget
+			}
+		}
+		if cfg.Badge.Tiers {
+			opts.Tiers = cfg.PowerLevel.Tiers
+			if len(opts.Tiers) == 0 {
+				opts.Tiers = powerlevel.DefaultTiers()
+			}
+		}
+	}
+	return opts
+}
+
+// fileSize reports a file's size for humans, e.g. "48.2 KB"
+func fileSize(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "size unknown"
+	}
+	size := float64(info.Size())
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", size/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", size/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", info.Size())
+	}
+}
+
+// cropOffset returns the configured crop offset for an emblem entry, if any
+func cropOffset(cfg *config.Config, ref string) *config.CropOffset {
+	if cfg == nil {
+		return nil
+	}
+	if offset, ok := cfg.Emblems.CropOffsets[ref]; ok {
+		return &offset
+	}
+	return nil
+}
+
+// customRef returns ref when it is custom artwork, otherwise ""
+func customRef(ref string) string {
+	if emblem.IsCustom(ref) {
+		return ref
+	}
+	return ""
+}
+
+// selectEmblem picks the weekly emblem using the configured strategy,
+// falling back to the JSON rotation file when no config is loaded.
+// stats gates emblems.unlocks; nil unlocks nothing, so only ungated
+// emblems (or the fallback) can be chosen.
+func selectEmblem(cfg *config.Config, stats *github.Stats) (string, error) {
+	if cfg == nil {
+		return emblem.SelectEmblem(workspace.New(cfg).EmblemConfig())
+	}
+	selector := &emblem.Selector{Config: &cfg.Emblems, Username: getUsername(cfg)}
+	if len(cfg.Emblems.Unlocks) > 0 {
+		selector.Stats = &emblem.Stats{}
+		if stats != nil {
+			progress, err := emblemStats(cfg, stats)
+			if err != nil {
+				return "", err
+			}
+			selector.Stats = progress
+		}
+	}
+	return selector.Select()
+}
+
 // getUsername returns the username from config, falling back to GITHUB_ACTOR env var
 func getUsername(cfg *config.Config) string {
 	if cfg != nil && cfg.Username != "" {
@@ -209,6 +511,28 @@ func getUsername(cfg *config.Config) string {
 	return os.Getenv("GITHUB_ACTOR")
 }
 
+// newBadgeStats converts fetched GitHub stats into badge stats, selecting the
+// stat bar metrics from config (defaults apply when cfg is nil)
+func newBadgeStats(cfg *config.Config, stats *github.Stats) *badge.Stats {
+	badgeStats := badge.FromGitHub(getUsername(cfg), stats)
+	if cfg != nil {
+		badgeStats.Metrics = cfg.Metrics.Enabled()
+		if cfg.Badge.LanguageBar {
+			badgeStats.Languages = badge.TopLanguages(stats.Languages, cfg.Badge.TopLanguages)
+		}
+		if cfg.Badge.Deltas {
+			badgeStats.Deltas = weeklyDeltas(cfg, stats)
+		}
+		if cfg.Badge.Sparkline.Enabled {
+			badgeStats.Sparkline = sparklineSeries(cfg, stats)
+		}
+		if cfg.Achievements.Enabled {
+			badgeStats.Triumphs = triumphs(cfg)
+		}
+	}
+	return badgeStats
+}
+
 type demoUser struct {
 	username   string
 	emblemHash string
@@ -225,7 +549,7 @@ var demoUsers = []demoUser{
 	{"mrbobbytables", "1661191194", 423, 98, 156, 312, 634},
 }
 
-func generateDemos() error {
+func generateDemos(ws *workspace.Workspace) error {
 	// Validate BUNGIE_API_KEY
 	if os.Getenv("BUNGIE_API_KEY") == "" {
 		return fmt.Errorf("BUNGIE_API_KEY environment variable not set\nGet your API key from https://www.bungie.net/en/Application")
@@ -233,7 +557,7 @@ func generateDemos() error {
 
 	// Create directories
 	os.MkdirAll("examples", 0755)
-	os.MkdirAll("data", 0755)
+	os.MkdirAll(ws.DataDir, 0755)
 
 	for _, user := range demoUsers {
 		fmt.Printf("\n=== Generating badge for @%s ===\n", user.username)
@@ -248,17 +572,16 @@ func generateDemos() error {
 			Reviews:       user.reviews,
 			StarsReceived: user.stars,
 		}
-		statsJSON, _ := json.MarshalIndent(stats, "", "  ")
-		if err := os.WriteFile("data/stats.json", statsJSON, 0644); err != nil {
+		if err := cache.Save(ws.Stats(), user.username, &stats, time.Now()); err != nil {
 			return fmt.Errorf("writing stats for %s: %w", user.username, err)
 		}
 
 		// Delete cached emblem to force fresh fetch
-		os.Remove("data/emblem.jpg")
+		os.Remove(ws.Emblem())
 
 		// Fetch emblem
 		fmt.Printf("Fetching emblem %s...\n", user.emblemHash)
-		if err := bungie.FetchEmblem(user.emblemHash); err != nil {
+		if err := bungie.FetchEmblem(ws.Manifest(), user.emblemHash, ws.Emblem()); err != nil {
 			return fmt.Errorf("fetching emblem for %s: %w", user.username, err)
 		}
 
@@ -273,7 +596,7 @@ func generateDemos() error {
 			Stars:        user.stars,
 		}
 		outputPath := fmt.Sprintf("examples/%s.png", user.username)
-		if err := badge.Generate("data/emblem.jpg", badgeStats, outputPath); err != nil {
+		if err := badge.Generate(ws.Emblem(), badgeStats, outputPath); err != nil {
 			return fmt.Errorf("generating badge for %s: %w", user.username, err)
 		}
 
@@ -285,14 +608,23 @@ func generateDemos() error {
 }
 
 func printUsage() {
-	fmt.Fprintf(os.Stderr, "Usage: contribemblem <command>\n\n")
+	fmt.Fprintf(os.Stderr, "Usage: contribemblem [--config path] [--workdir dir] <command>\n\n")
+	fmt.Fprintf(os.Stderr, "Global flags:\n")
+	fmt.Fprintf(os.Stderr, "  --config path    Config file, relative to --workdir (default %s)\n", config.DefaultConfigPath)
+	fmt.Fprintf(os.Stderr, "  --workdir dir    Profile repository to run in, e.g. a checkout elsewhere\n\n")
 	fmt.Fprintf(os.Stderr, "Commands:\n")
-	fmt.Fprintf(os.Stderr, "  fetch-stats      Fetch GitHub stats via GraphQL\n")
-	fmt.Fprintf(os.Stderr, "  select-emblem    Select weekly emblem hash\n")
+	fmt.Fprintf(os.Stderr, "  fetch-stats      Fetch GitHub stats via GraphQL, reusing fresh cached stats (--force-refresh)\n")
+	fmt.Fprintf(os.Stderr, "  select-emblem    Select weekly emblem, printing hash and name (--json)\n")
 	fmt.Fprintf(os.Stderr, "  fetch-emblem     Fetch emblem image from Bungie API\n")
-	fmt.Fprintf(os.Stderr, "  generate         Generate badge image\n")
+	fmt.Fprintf(os.Stderr, "  emblems          Browse cached emblems (search <text> | list [--source] [--season] [--yaml] | preview <hash...>)\n")
+	fmt.Fprintf(os.Stderr, "  generate         Generate badge image (and the animated badge if enabled)\n")
+	fmt.Fprintf(os.Stderr, "  power-level      Print Power Level from the cached stats (--breakdown for details)\n")
+	fmt.Fprintf(os.Stderr, "  history          List archived weekly stats or diff two weeks (list | diff [from] [to])\n")
 	fmt.Fprintf(os.Stderr, "  update-readme    Update README with badge and timestamp\n")
-	fmt.Fprintf(os.Stderr, "  run              Run full pipeline\n")
+	fmt.Fprintf(os.Stderr, "  validate         Check config and emblem hashes against the Bungie manifest\n")
+	fmt.Fprintf(os.Stderr, "  run              Run full pipeline (--strict validates emblems first, --force-refresh refetches stats)\n")
+	fmt.Fprintf(os.Stderr, "  serve            Serve live badges at /badge/{username}.png and .svg (--addr, --ttl)\n")
+	fmt.Fprintf(os.Stderr, "  paths            Print the workspace paths run reads and writes as name=path lines (for $GITHUB_OUTPUT)\n")
 	fmt.Fprintf(os.Stderr, "  generate-demos   Generate example badges for demo users\n")
 	fmt.Fprintf(os.Stderr, "  help             Show this help message\n")
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/emblem"
+	"github.com/castrojo/contribemblem/internal/server"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+// runServe implements `serve [--addr :8080] [--ttl 1h]`, rendering badges
+// on demand at /badge/{username}.png and .svg
+func runServe(cfg *config.Config, configPath string, args []string) error {
+	if cfg == nil {
+		return fmt.Errorf("serve requires %s", configPath)
+	}
+
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", cfg.Serve.Address(), "address to listen on")
+	ttl := fs.Duration("ttl", cfg.Serve.CacheTTL(), "how long fetched stats and emblems are reused")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *ttl <= 0 {
+		return fmt.Errorf("--ttl must be positive")
+	}
+
+	if os.Getenv("GITHUB_TOKEN") == "" {
+		return fmt.Errorf("GITHUB_TOKEN environment variable not set")
+	}
+	// Bungie emblems are resolved against the manifest, parsed once here
+	var manifest *bungie.Manifest
+	for _, ref := range cfg.Emblems.Hashes() {
+		if !emblem.IsCustom(ref) {
+			var err error
+			if manifest, err = openManifest(cfg); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	srv := &server.Server{
+		Config:  cfg,
+		Options: badgeOptions(cfg),
+		Stats:   server.GitHubStats(nil),
+		Art:     server.BungieArt(manifest, workspace.New(cfg).ServeArt(), nil),
+		TTL:     *ttl,
+	}
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Printf("✓ Serving badges at http://%s/badge/{username}.png (cache %v)\n", *addr, *ttl)
+	return httpServer.ListenAndServe()
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/cache"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+// statsTTL is how long cached stats are reused (cache.ttl, default 6h)
+func statsTTL(cfg *config.Config) time.Duration {
+	if cfg == nil {
+		return config.DefaultStatsTTL
+	}
+	return cfg.Cache.StatsTTL()
+}
+
+// fetchStats reuses the cached stats when they are fresh for the
+// configured user, otherwise fetches from GitHub and caches the result.
+// forceRefresh always fetches. cached reports whether the stats came from
+// the cache.
+func fetchStats(cfg *config.Config, forceRefresh bool) (stats *github.Stats, cached bool, err error) {
+	username := getUsername(cfg)
+	path := workspace.New(cfg).Stats()
+	now := time.Now()
+	if !forceRefresh {
+		if entry, err := cache.Load(path); err == nil && entry.Fresh(username, statsTTL(cfg), now) {
+			fmt.Fprintf(os.Stderr, "Using cached stats from %s (fetched %s ago, --force-refresh to refetch)\n",
+				path, cache.FormatAge(now.Sub(entry.Meta.FetchedAt)))
+			return entry.Stats, true, nil
+		}
+	}
+
+	stats, err = github.FetchStats(username, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := cache.Save(path, username, stats, now); err != nil {
+		return nil, false, err
+	}
+	return stats, false, nil
+}
+
+// loadCachedStats reads the cached stats for badge generation, warning
+// when they are stale or belong to another user or year
+func loadCachedStats(cfg *config.Config) (*github.Stats, error) {
+	path := workspace.New(cfg).Stats()
+	entry, err := cache.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if problems := entry.Problems(getUsername(cfg), statsTTL(cfg), time.Now()); len(problems) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %s may be out of date (%s); refresh with fetch-stats --force-refresh\n",
+			path, strings.Join(problems, ", "))
+	}
+	return entry.Stats, nil
+}
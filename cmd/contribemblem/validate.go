@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/emblem"
+	"github.com/castrojo/contribemblem/internal/workspace"
+)
+
+// validateEmblems checks every configured emblem: Bungie hashes and names
+// against the manifest, custom artwork for size and aspect ratio. It prints
+// one line per emblem.
+func validateEmblems(cfg *config.Config) error {
+	refs := cfg.Emblems.Hashes()
+
+	problems := make(map[string]string)
+	var manifestRefs []string
+	for _, ref := range refs {
+		if !emblem.IsCustom(ref) {
+			manifestRefs = append(manifestRefs, ref)
+			continue
+		}
+		if err := emblem.CheckCustom(ref); err != nil {
+			problems[ref] = err.Error()
+		}
+	}
+
+	// Custom-only rotations don't need a Bungie API key
+	if len(manifestRefs) > 0 {
+		manifest := workspace.New(cfg).Manifest()
+		if err := bungie.EnsureManifest(manifest); err != nil {
+			return err
+		}
+		issues, err := bungie.CheckEmblems(manifest, manifestRefs)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			problems[issue.Ref] = issue.Problem
+		}
+	}
+
+	for _, ref := range refs {
+		if problem, ok := problems[ref]; ok {
+			fmt.Printf("✗ emblem %s: %s\n", ref, problem)
+		} else {
+			fmt.Printf("✓ emblem %s\n", ref)
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%d of %d emblem(s) failed validation", len(problems), len(refs))
+	}
+	return nil
+}
@@ -2,9 +2,9 @@ module github.com/castrojo/contribemblem
 
 go 1.24.0
 
-require golang.org/x/image v0.35.0
-
 require (
-	golang.org/x/text v0.33.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	golang.org/x/image v0.35.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/text v0.33.0 // indirect
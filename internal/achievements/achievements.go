@@ -0,0 +1,136 @@
+package achievements
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/history"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+)
+
+// Unlock records when a triumph was earned
+type Unlock struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Icon       string `json:"icon"`
+	UnlockedAt string `json:"unlocked_at"` // YYYY-MM-DD
+}
+
+// Ledger holds every unlock by rule ID. Unlocks are permanent: stats reset
+// every January, but a triumph once earned stays earned.
+type Ledger map[string]Unlock
+
+// DefaultRules returns the built-in milestones
+func DefaultRules() []config.AchievementRule {
+	return config.DefaultAchievementRules()
+}
+
+// Evaluate unlocks every rule met by the current stats or any archived
+// snapshot, adding them to ledger. The unlock date is the earliest snapshot
+// that met the rule, or now for rules only the current stats meet.
+// Returns the newly unlocked triumphs in rule order.
+func Evaluate(rules []config.AchievementRule, stats *github.Stats, snapshots []history.Snapshot, ledger Ledger, now time.Time) []Unlock {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	var unlocked []Unlock
+	for _, rule := range rules {
+		if _, ok := ledger[rule.ID]; ok {
+			continue
+		}
+
+		date := ""
+		for _, snap := range snapshots {
+			if met(rule, snap.Stats) {
+				date = snapshotDate(snap, now)
+				break
+			}
+		}
+		if date == "" && stats != nil && met(rule, stats) {
+			date = now.UTC().Format("2006-01-02")
+		}
+		if date == "" {
+			continue
+		}
+
+		icon := rule.Icon
+		if icon == "" {
+			icon = config.IconStar
+		}
+		unlock := Unlock{ID: rule.ID, Name: rule.Name, Icon: icon, UnlockedAt: date}
+		ledger[rule.ID] = unlock
+		unlocked = append(unlocked, unlock)
+	}
+	return unlocked
+}
+
+// Recent returns up to n unlocks, most recently earned first
+func (l Ledger) Recent(n int) []Unlock {
+	unlocks := make([]Unlock, 0, len(l))
+	for _, u := range l {
+		unlocks = append(unlocks, u)
+	}
+	sort.Slice(unlocks, func(i, j int) bool {
+		if unlocks[i].UnlockedAt != unlocks[j].UnlockedAt {
+			return unlocks[i].UnlockedAt > unlocks[j].UnlockedAt
+		}
+		return unlocks[i].ID < unlocks[j].ID
+	})
+	if len(unlocks) > n {
+		unlocks = unlocks[:n]
+	}
+	return unlocks
+}
+
+// LoadLedger reads the unlock ledger; a missing file is an empty ledger
+func LoadLedger(path string) (Ledger, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Ledger{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read achievements: %w", err)
+	}
+
+	ledger := Ledger{}
+	if err := json.Unmarshal(data, &ledger); err != nil {
+		return nil, fmt.Errorf("failed to parse achievements: %w", err)
+	}
+	return ledger, nil
+}
+
+// SaveLedger writes the unlock ledger as indented JSON
+func SaveLedger(path string, ledger Ledger) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create achievements directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal achievements: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write achievements: %w", err)
+	}
+	return nil
+}
+
+// met reports whether stats satisfy the rule
+func met(rule config.AchievementRule, stats *github.Stats) bool {
+	return powerlevel.FromStats(stats)[rule.Metric] >= rule.Threshold
+}
+
+// snapshotDate is the day a snapshot's stats were fetched
+func snapshotDate(snap history.Snapshot, now time.Time) string {
+	if t, err := time.Parse(time.RFC3339, snap.Stats.UpdatedAt); err == nil {
+		return t.UTC().Format("2006-01-02")
+	}
+	return now.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,113 @@
+package achievements
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/history"
+)
+
+var testRules = []config.AchievementRule{
+	{ID: "reviews-100", Name: "100 Reviews", Metric: config.MetricReviews, Threshold: 100},
+	{ID: "streak-30", Name: "30-Day Streak", Metric: config.MetricLongestStreak, Threshold: 30, Icon: config.IconDiamond},
+	{ID: "stars-1k", Name: "First 1K Stars", Metric: config.MetricStars, Threshold: 1000},
+}
+
+func TestEvaluateUsesEarliestSnapshot(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	snapshots := []history.Snapshot{
+		{Week: "2026-W05", Stats: &github.Stats{UpdatedAt: "2026-01-26T00:00:00Z", Reviews: 80}},
+		{Week: "2026-W06", Stats: &github.Stats{UpdatedAt: "2026-02-02T00:00:00Z", Reviews: 104}},
+		{Week: "2026-W07", Stats: &github.Stats{UpdatedAt: "2026-02-09T00:00:00Z", Reviews: 130}},
+	}
+	current := &github.Stats{Reviews: 150, LongestStreak: 31}
+	ledger := Ledger{}
+
+	unlocked := Evaluate(testRules, current, snapshots, ledger, now)
+	if len(unlocked) != 2 {
+		t.Fatalf("expected 2 unlocks, got %+v", unlocked)
+	}
+
+	if got := ledger["reviews-100"].UnlockedAt; got != "2026-02-02" {
+		t.Errorf("reviews-100 unlocked %s, want earliest snapshot 2026-02-02", got)
+	}
+	if got := ledger["streak-30"].UnlockedAt; got != "2026-03-01" {
+		t.Errorf("streak-30 unlocked %s, want now 2026-03-01", got)
+	}
+	if ledger["reviews-100"].Icon != config.IconStar {
+		t.Errorf("expected default star icon, got %s", ledger["reviews-100"].Icon)
+	}
+	if _, ok := ledger["stars-1k"]; ok {
+		t.Error("stars-1k should stay locked")
+	}
+}
+
+func TestEvaluateUnlocksArePermanent(t *testing.T) {
+	ledger := Ledger{
+		"reviews-100": {ID: "reviews-100", Name: "100 Reviews", UnlockedAt: "2025-06-01"},
+	}
+
+	// New year: reviews reset, but the triumph stays and keeps its date
+	unlocked := Evaluate(testRules, &github.Stats{Reviews: 3}, nil, ledger, time.Now())
+	if len(unlocked) != 0 {
+		t.Errorf("expected no new unlocks, got %+v", unlocked)
+	}
+	if ledger["reviews-100"].UnlockedAt != "2025-06-01" {
+		t.Errorf("unlock date changed to %s", ledger["reviews-100"].UnlockedAt)
+	}
+}
+
+func TestEvaluateDefaultRules(t *testing.T) {
+	ledger := Ledger{}
+	Evaluate(nil, &github.Stats{StarsReceived: 1247}, nil, ledger, time.Now())
+
+	if _, ok := ledger["stars-1k"]; !ok {
+		t.Error("expected default stars-1k rule to unlock")
+	}
+}
+
+func TestLedgerRecent(t *testing.T) {
+	ledger := Ledger{
+		"a": {ID: "a", UnlockedAt: "2026-01-05"},
+		"b": {ID: "b", UnlockedAt: "2026-03-01"},
+		"c": {ID: "c", UnlockedAt: "2026-02-10"},
+	}
+
+	recent := ledger.Recent(2)
+	if len(recent) != 2 || recent[0].ID != "b" || recent[1].ID != "c" {
+		t.Errorf("Recent(2) = %+v, want b then c", recent)
+	}
+}
+
+func TestLedgerRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "achievements.json")
+
+	// Missing ledger is empty
+	ledger, err := LoadLedger(path)
+	if err != nil || len(ledger) != 0 {
+		t.Fatalf("LoadLedger(missing) = %v, %v; want empty ledger", ledger, err)
+	}
+
+	ledger["stars-1k"] = Unlock{ID: "stars-1k", Name: "First 1K Stars", Icon: config.IconStar, UnlockedAt: "2026-02-06"}
+	if err := SaveLedger(path, ledger); err != nil {
+		t.Fatalf("SaveLedger() error = %v", err)
+	}
+
+	loaded, err := LoadLedger(path)
+	if err != nil {
+		t.Fatalf("LoadLedger() error = %v", err)
+	}
+	if loaded["stars-1k"] != ledger["stars-1k"] {
+		t.Errorf("round trip mismatch: %+v", loaded["stars-1k"])
+	}
+}
+
+func TestDefaultRulesValid(t *testing.T) {
+	cfg := config.AchievementsConfig{Rules: DefaultRules()}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default rules should validate: %v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package badge
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/gif"
+	"io"
+	"math"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// Animation configures an animated badge
+type Animation struct {
+	// Format is config.FormatGIF (default) or config.FormatAPNG
+	Format string
+	// Effects combines config.EffectCountUp, EffectShimmer and EffectRotation
+	Effects []string
+	// Frames per loop (default config.DefaultAnimationFrames)
+	Frames int
+	// Duration of one loop (default config.DefaultAnimationLoop)
+	Duration time.Duration
+	// Emblems are more emblem artworks the rotation effect cross-fades
+	// through after the main emblem. They use the crop strategy but not
+	// Options.CropOffset, which belongs to the main emblem.
+	Emblems []image.Image
+}
+
+// Animation timing
+const (
+	countUpShare = 0.7  // the count-up finishes 70% into the loop, then holds
+	rotationFade = 0.35 // share of each emblem's slot spent cross-fading out
+	shimmerWidth = 90   // px half-width of the shimmer band
+	shimmerBloom = 6    // px the shimmer glows below the accent line
+)
+
+// frame is the animation state of a single rendered frame
+type frame struct {
+	CountUp float64 // share of the Power Level shown, 0-1
+	Shimmer float64 // shimmer band position along the accent line, 0-1; negative hides it
+}
+
+// powerLevel is the Power Level shown at this point of the count-up
+func (f *frame) powerLevel(final int) int {
+	return int(math.Round(float64(final) * f.CountUp))
+}
+
+func (a *Animation) frameCount() int {
+	if a.Frames <= 0 {
+		return config.DefaultAnimationFrames
+	}
+	return a.Frames
+}
+
+// frameDelay is how long each frame shows
+func (a *Animation) frameDelay() time.Duration {
+	loop := a.Duration
+	if loop <= 0 {
+		loop = config.DefaultAnimationLoop
+	}
+	return loop / time.Duration(a.frameCount())
+}
+
+func (a *Animation) has(effect string) bool {
+	for _, e := range a.Effects {
+		if e == effect {
+			return true
+		}
+	}
+	return false
+}
+
+// animationFormat is the animated format to write, or "" when static
+func (o Options) animationFormat() string {
+	switch {
+	case o.Animation == nil:
+		return ""
+	case o.Animation.Format == config.FormatAPNG:
+		return config.FormatAPNG
+	default:
+		return config.FormatGIF
+	}
+}
+
+// EncodeAnimation renders opts.Animation's frames over emblem and writes
+// them to w as a looping GIF or APNG
+func EncodeAnimation(ctx context.Context, w io.Writer, emblem image.Image, stats Stats, opts Options) error {
+	if opts.Animation == nil {
+		return fmt.Errorf("no animation configured")
+	}
+
+	emblems := []image.Image{emblem}
+	if opts.Animation.has(config.EffectRotation) {
+		emblems = append(emblems, opts.Animation.Emblems...)
+	}
+	frames, err := renderAnimation(ctx, emblems, &stats, opts)
+	if err != nil {
+		return err
+	}
+
+	delay := opts.Animation.frameDelay()
+	if opts.animationFormat() == config.FormatAPNG {
+		err = encodeAPNG(w, frames, delay, opts.Encoding.Compression)
+	} else {
+		err = encodeGIF(w, frames, delay)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode animation: %w", err)
+	}
+	return nil
+}
+
+// renderAnimation renders one loop of frames. The last frame always shows
+// the final Power Level; rotation cycles back to the first emblem.
+func renderAnimation(ctx context.Context, emblems []image.Image, stats *Stats, opts Options) ([]*image.RGBA, error) {
+	anim := opts.Animation
+	n := anim.frameCount()
+	rotate := anim.has(config.EffectRotation) && len(emblems) > 1
+
+	frames := make([]*image.RGBA, n)
+	for i := range frames {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		t := float64(i) / float64(n)
+		state := &frame{CountUp: 1, Shimmer: -1}
+		if anim.has(config.EffectCountUp) {
+			state.CountUp = easeOutCubic(math.Min(1, t/countUpShare))
+		}
+		if anim.has(config.EffectShimmer) {
+			state.Shimmer = t
+		}
+
+		frameOpts := opts
+		frameOpts.Animation = nil
+		frameOpts.frame = state
+		if !rotate {
+			img, err := render(emblems[0], stats, frameOpts)
+			if err != nil {
+				return nil, err
+			}
+			frames[i] = img
+			continue
+		}
+
+		// Each emblem holds for its slot, then cross-fades into the next
+		slot := t * float64(len(emblems))
+		current := int(slot)
+		img, err := renderEmblemFrame(emblems, current, stats, frameOpts)
+		if err != nil {
+			return nil, err
+		}
+		if fade := (slot - float64(current) - (1 - rotationFade)) / rotationFade; fade > 0 {
+			next, err := renderEmblemFrame(emblems, (current+1)%len(emblems), stats, frameOpts)
+			if err != nil {
+				return nil, err
+			}
+			blendFrames(img, next, fade)
+		}
+		frames[i] = img
+	}
+	return frames, nil
+}
+
+// renderEmblemFrame renders a frame over the i-th emblem of a rotation
+func renderEmblemFrame(emblems []image.Image, i int, stats *Stats, opts Options) (*image.RGBA, error) {
+	if i > 0 {
+		opts.CropOffset = nil
+	}
+	return render(emblems[i], stats, opts)
+}
+
+// blendFrames mixes src into dst by t (0 keeps dst, 1 is src)
+func blendFrames(dst, src *image.RGBA, t float64) {
+	for i := range dst.Pix {
+		dst.Pix[i] = uint8(math.Round(float64(dst.Pix[i])*(1-t) + float64(src.Pix[i])*t))
+	}
+}
+
+// drawShimmer sweeps a bright band across the accent line with a soft
+// bloom beneath it; pos 0 and 1 put the band just off either edge so the
+// loop is seamless
+func drawShimmer(canvas *image.RGBA, pos float64) {
+	center := -shimmerWidth + pos*float64(Width+2*shimmerWidth)
+	for x := int(center) - shimmerWidth; x <= int(center)+shimmerWidth; x++ {
+		if x < 0 || x >= Width {
+			continue
+		}
+		d := (float64(x) - center) / shimmerWidth
+		strength := 0.8 * math.Exp(-4*d*d)
+		for y := 0; y < accentHeight+shimmerBloom; y++ {
+			s := strength
+			if y >= accentHeight {
+				s *= 0.4 * (1 - float64(y-accentHeight)/shimmerBloom)
+			}
+			canvas.SetRGBA(x, y, blend(canvas.RGBAAt(x, y), WhiteColor, s))
+		}
+	}
+}
+
+func easeOutCubic(t float64) float64 {
+	return 1 - math.Pow(1-t, 3)
+}
+
+// encodeGIF writes frames as a looping GIF with one shared palette. After
+// the first frame only the changed region is stored.
+func encodeGIF(w io.Writer, frames []*image.RGBA, delay time.Duration) error {
+	pal := medianCut(frames, 256)
+	q := newQuantizer(pal)
+
+	// GIF delays are in 1/100s; browsers slow anything under 2 to 10
+	cs := int(math.Round(delay.Seconds() * 100))
+	if cs < 2 {
+		cs = 2
+	}
+
+	anim := &gif.GIF{
+		Config: image.Config{ColorModel: pal, Width: Width, Height: Height},
+	}
+	for i, img := range frames {
+		r := img.Bounds()
+		if i > 0 {
+			r = changedRect(frames[i-1], img)
+		}
+		anim.Image = append(anim.Image, q.paletted(img, r))
+		anim.Delay = append(anim.Delay, cs)
+		anim.Disposal = append(anim.Disposal, gif.DisposalNone)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// changedRect is the bounding box of pixels that differ between two
+// frames, or a single pixel when they're identical
+func changedRect(prev, cur *image.RGBA) image.Rectangle {
+	b := cur.Bounds()
+	r := image.Rectangle{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := cur.PixOffset(x, y)
+			if prev.Pix[i] != cur.Pix[i] || prev.Pix[i+1] != cur.Pix[i+1] ||
+				prev.Pix[i+2] != cur.Pix[i+2] || prev.Pix[i+3] != cur.Pix[i+3] {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	if r.Empty() {
+		return image.Rect(b.Min.X, b.Min.Y, b.Min.X+1, b.Min.Y+1)
+	}
+	return r
+}
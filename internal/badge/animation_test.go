@@ -0,0 +1,215 @@
+package badge
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+func TestRenderAnimation(t *testing.T) {
+	stats := &Stats{Username: "testuser", Commits: 1500, PullRequests: 420, Issues: 18, Reviews: 67, Stars: 23}
+	art := stripedArt()
+	static, err := render(art, stats, Options{})
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+
+	t.Run("count-up ends on the static badge", func(t *testing.T) {
+		opts := Options{Animation: &Animation{Effects: []string{config.EffectCountUp}, Frames: 10}}
+		frames, err := renderAnimation(context.Background(), []image.Image{art}, stats, opts)
+		if err != nil {
+			t.Fatalf("renderAnimation() error = %v", err)
+		}
+		if len(frames) != 10 {
+			t.Fatalf("got %d frames, want 10", len(frames))
+		}
+		if bytes.Equal(frames[0].Pix, static.Pix) {
+			t.Error("first frame should still be counting up")
+		}
+		if !bytes.Equal(frames[9].Pix, static.Pix) {
+			t.Error("last frame should show the final Power Level")
+		}
+	})
+
+	t.Run("shimmer brightens the accent line", func(t *testing.T) {
+		opts := Options{Animation: &Animation{Effects: []string{config.EffectShimmer}, Frames: 10}}
+		frames, err := renderAnimation(context.Background(), []image.Image{art}, stats, opts)
+		if err != nil {
+			t.Fatalf("renderAnimation() error = %v", err)
+		}
+		// Frame 5 centers the band at the middle of the badge
+		before, after := static.RGBAAt(Width/2, 1), frames[5].RGBAAt(Width/2, 1)
+		if relativeLuminance(after) <= relativeLuminance(before) {
+			t.Errorf("shimmer pixel %v is not brighter than %v", after, before)
+		}
+		if got := frames[5].RGBAAt(Width/2, Height/2); got != static.RGBAAt(Width/2, Height/2) {
+			t.Errorf("shimmer changed the badge body: %v", got)
+		}
+	})
+
+	t.Run("rotation cycles emblems", func(t *testing.T) {
+		red := solidArt(color.RGBA{200, 30, 30, 255})
+		blue := solidArt(color.RGBA{30, 30, 200, 255})
+		opts := Options{Animation: &Animation{Effects: []string{config.EffectRotation}, Frames: 8}}
+		frames, err := renderAnimation(context.Background(), []image.Image{red, blue}, stats, opts)
+		if err != nil {
+			t.Fatalf("renderAnimation() error = %v", err)
+		}
+
+		// Slots: frames 0-3 red (fading out at 3), 4-7 blue (fading back at 7)
+		bg := func(i int) color.RGBA { return frames[i].RGBAAt(20, 60) }
+		if c := bg(0); c.R <= c.B {
+			t.Errorf("frame 0 background %v, want red", c)
+		}
+		if c := bg(4); c.B <= c.R {
+			t.Errorf("frame 4 background %v, want blue", c)
+		}
+		if c, r, b := bg(3), bg(0), bg(4); c == r || c == b {
+			t.Errorf("frame 3 background %v should be mid cross-fade", c)
+		}
+	})
+}
+
+func TestEncodeGIF(t *testing.T) {
+	stats := &Stats{Username: "testuser", Commits: 150}
+	opts := Options{Animation: &Animation{Effects: []string{config.EffectCountUp, config.EffectShimmer}, Frames: 6}}
+	frames, err := renderAnimation(context.Background(), []image.Image{stripedArt()}, stats, opts)
+	if err != nil {
+		t.Fatalf("renderAnimation() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeGIF(&buf, frames, 250*time.Millisecond); err != nil {
+		t.Fatalf("encodeGIF() error = %v", err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() error = %v", err)
+	}
+
+	if len(g.Image) != 6 {
+		t.Fatalf("got %d frames, want 6", len(g.Image))
+	}
+	if g.LoopCount != 0 {
+		t.Errorf("LoopCount = %d, want 0 (forever)", g.LoopCount)
+	}
+	for i, d := range g.Delay {
+		if d != 25 {
+			t.Errorf("frame %d delay = %d, want 25", i, d)
+		}
+	}
+	if g.Config.Width != Width || g.Config.Height != Height {
+		t.Errorf("size = %dx%d, want %dx%d", g.Config.Width, g.Config.Height, Width, Height)
+	}
+	if g.Image[0].Bounds() != image.Rect(0, 0, Width, Height) {
+		t.Errorf("first frame bounds = %v, want the full badge", g.Image[0].Bounds())
+	}
+	// Later frames only store what changed (accent line down to the Power Level)
+	if b := g.Image[1].Bounds(); b.Max.Y >= Height-statBarHeight {
+		t.Errorf("frame 1 bounds = %v, want only the changed region", b)
+	}
+}
+
+func TestEncodeAPNG(t *testing.T) {
+	stats := &Stats{Username: "testuser", Commits: 150}
+	opts := Options{Animation: &Animation{Effects: []string{config.EffectCountUp}, Frames: 4}}
+	frames, err := renderAnimation(context.Background(), []image.Image{stripedArt()}, stats, opts)
+	if err != nil {
+		t.Fatalf("renderAnimation() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeAPNG(&buf, frames, 100*time.Millisecond, png.DefaultCompression); err != nil {
+		t.Fatalf("encodeAPNG() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	// Plain PNG decoders show the default image: the finished badge
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	last := frames[len(frames)-1]
+	for y := 0; y < Height; y += 7 {
+		for x := 0; x < Width; x += 7 {
+			r, g, b, a := img.At(x, y).RGBA()
+			want := last.RGBAAt(x, y)
+			if uint8(r>>8) != want.R || uint8(g>>8) != want.G || uint8(b>>8) != want.B || uint8(a>>8) != want.A {
+				t.Fatalf("default image pixel (%d,%d) differs from the last frame", x, y)
+			}
+		}
+	}
+
+	// Walk the chunks: acTL frame count, then fcTL/fdAT with increasing
+	// sequence numbers
+	var names []string
+	var seqs []uint32
+	var numFrames uint32
+	for pos := len(pngSignature); pos < len(data); {
+		length := binary.BigEndian.Uint32(data[pos:])
+		name := string(data[pos+4 : pos+8])
+		body := data[pos+8 : pos+8+int(length)]
+		names = append(names, name)
+		switch name {
+		case "acTL":
+			numFrames = binary.BigEndian.Uint32(body)
+		case "fcTL", "fdAT":
+			seqs = append(seqs, binary.BigEndian.Uint32(body))
+		}
+		pos += 12 + int(length)
+	}
+
+	if numFrames != 4 {
+		t.Errorf("acTL num_frames = %d, want 4", numFrames)
+	}
+	if names[0] != "IHDR" || names[1] != "acTL" || names[2] != "IDAT" || names[len(names)-1] != "IEND" {
+		t.Errorf("chunk order = %v", names)
+	}
+	for i, seq := range seqs {
+		if seq != uint32(i) {
+			t.Fatalf("sequence numbers = %v, want 0..%d", seqs, len(seqs)-1)
+		}
+	}
+	if len(seqs) != 8 {
+		t.Errorf("got %d fcTL/fdAT chunks, want 8", len(seqs))
+	}
+}
+
+func TestAPNGDelay(t *testing.T) {
+	tests := []struct {
+		delay    time.Duration
+		num, den uint16
+	}{
+		{100 * time.Millisecond, 100, 1000},
+		{65535 * time.Millisecond, 65535, 1000},
+		{100 * time.Second, 10000, 100},
+		{time.Hour, 65535, 100},
+	}
+	for _, tt := range tests {
+		if num, den := apngDelay(tt.delay); num != tt.num || den != tt.den {
+			t.Errorf("apngDelay(%v) = %d/%d, want %d/%d", tt.delay, num, den, tt.num, tt.den)
+		}
+	}
+}
+
+func TestMedianCut(t *testing.T) {
+	img := splitArt(color.RGBA{200, 30, 30, 255}, color.RGBA{30, 30, 200, 255}, 0.5)
+
+	pal := medianCut([]*image.RGBA{img}, 256)
+	if len(pal) != 2 {
+		t.Fatalf("palette has %d colors, want 2 for a two-color image", len(pal))
+	}
+
+	pal = medianCut([]*image.RGBA{stripedArt(), img}, 3)
+	if len(pal) > 3 {
+		t.Errorf("palette has %d colors, want at most 3", len(pal))
+	}
+}
@@ -0,0 +1,181 @@
+package badge
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"time"
+)
+
+// pngSignature starts every PNG (and APNG) file
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// APNG frame disposal and blending (fcTL dispose_op and blend_op)
+const (
+	apngDisposeNone = 0
+	apngBlendSource = 0
+)
+
+// apngDelay converts a frame delay to the fcTL's 16-bit fraction of a
+// second: milliseconds, or centiseconds for delays too long for those
+func apngDelay(delay time.Duration) (num, den uint16) {
+	if ms := delay.Milliseconds(); ms <= math.MaxUint16 {
+		return uint16(ms), 1000
+	}
+	return uint16(min(delay.Milliseconds()/10, math.MaxUint16)), 100
+}
+
+// encodeAPNG writes frames as a looping animated PNG. The default image
+// is the last frame, so viewers without APNG support show the finished
+// badge. After the first frame only the changed region is stored.
+func encodeAPNG(w io.Writer, frames []*image.RGBA, delay time.Duration, level png.CompressionLevel) error {
+	aw := &apngWriter{w: w}
+	aw.write(pngSignature)
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(Width))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(Height))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // truecolor with alpha
+	aw.chunk("IHDR", ihdr)
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+	binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+	aw.chunk("acTL", actl)
+
+	// No fcTL before IDAT keeps the default image out of the animation
+	last := frames[len(frames)-1]
+	data, err := compressRGBA(last, last.Bounds(), level)
+	if err != nil {
+		return err
+	}
+	aw.chunk("IDAT", data)
+
+	num, den := apngDelay(delay)
+	var seq uint32
+	for i, img := range frames {
+		r := img.Bounds()
+		if i > 0 {
+			r = changedRect(frames[i-1], img)
+		}
+
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(r.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(r.Dy()))
+		binary.BigEndian.PutUint32(fctl[12:], uint32(r.Min.X))
+		binary.BigEndian.PutUint32(fctl[16:], uint32(r.Min.Y))
+		binary.BigEndian.PutUint16(fctl[20:], num)
+		binary.BigEndian.PutUint16(fctl[22:], den)
+		fctl[24] = apngDisposeNone
+		fctl[25] = apngBlendSource
+		aw.chunk("fcTL", fctl)
+		seq++
+
+		data, err := compressRGBA(img, r, level)
+		if err != nil {
+			return err
+		}
+		fdat := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, seq)
+		aw.chunk("fdAT", append(fdat, data...))
+		seq++
+	}
+
+	aw.chunk("IEND", nil)
+	return aw.err
+}
+
+// apngWriter writes PNG chunks, keeping the first error
+type apngWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (aw *apngWriter) write(b []byte) {
+	if aw.err == nil {
+		_, aw.err = aw.w.Write(b)
+	}
+}
+
+// chunk writes a length-prefixed, CRC-suffixed PNG chunk
+func (aw *apngWriter) chunk(name string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], name)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+
+	aw.write(header)
+	aw.write(data)
+	aw.write(binary.BigEndian.AppendUint32(nil, crc.Sum32()))
+}
+
+// compressRGBA zlib-compresses the r region of img as 8-bit RGBA
+// scanlines, each Paeth-filtered
+func compressRGBA(img *image.RGBA, r image.Rectangle, level png.CompressionLevel) ([]byte, error) {
+	var buf bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&buf, zlibLevel(level))
+	if err != nil {
+		return nil, err
+	}
+
+	const bpp = 4
+	rowLen := r.Dx() * bpp
+	prev := make([]byte, rowLen)
+	line := make([]byte, 1+rowLen)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		cur := img.Pix[img.PixOffset(r.Min.X, y):][:rowLen]
+		line[0] = 4 // Paeth
+		for i := range cur {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = cur[i-bpp], prev[i-bpp]
+			}
+			line[1+i] = cur[i] - paeth(left, prev[i], upLeft)
+		}
+		if _, err := zw.Write(line); err != nil {
+			return nil, err
+		}
+		prev = cur
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// zlibLevel maps a PNG compression level to zlib's, like image/png does
+func zlibLevel(level png.CompressionLevel) int {
+	switch level {
+	case png.NoCompression:
+		return zlib.NoCompression
+	case png.BestSpeed:
+		return zlib.BestSpeed
+	case png.BestCompression:
+		return zlib.BestCompression
+	default:
+		return zlib.DefaultCompression
+	}
+}
+
+// paeth is the PNG Paeth predictor
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	default:
+		return c
+	}
+}
@@ -0,0 +1,150 @@
+package badge
+
+import (
+	"fmt"
+	"html/template"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// Contact sheet layout
+const (
+	sheetColumns       = 2
+	sheetPadding       = 16
+	sheetCaptionHeight = 26
+)
+
+// SheetBackground is the contact sheet backdrop
+var SheetBackground = color.RGBA{18, 18, 22, 255}
+
+// SheetEntry is one emblem on a contact sheet
+type SheetEntry struct {
+	Hash       string
+	Name       string
+	EmblemPath string // downloaded emblem artwork
+	// CropOffset overrides opts.CropOffset for this emblem
+	CropOffset *config.CropOffset
+}
+
+// caption is the text shown above an entry's badge
+func (e SheetEntry) caption() string {
+	if e.Name == "" {
+		return e.Hash
+	}
+	return e.Hash + "  " + e.Name
+}
+
+// GenerateContactSheet renders every entry as a full badge with the same
+// stats and writes <hash>.png per emblem, a contact-sheet.png grid and an
+// index.html page to dir
+func GenerateContactSheet(entries []SheetEntry, stats *Stats, opts Options, dir string) error {
+	if len(entries) == 0 {
+		return fmt.Errorf("no emblems to preview")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create preview directory: %w", err)
+	}
+
+	badges := make([]image.Image, len(entries))
+	for i, entry := range entries {
+		emblemImg, err := loadImage(entry.EmblemPath)
+		if err != nil {
+			return fmt.Errorf("failed to load emblem %s: %w", entry.Hash, err)
+		}
+		entryOpts := opts
+		if entry.CropOffset != nil {
+			entryOpts.CropOffset = entry.CropOffset
+		}
+		canvas, err := render(emblemImg, stats, entryOpts)
+		if err != nil {
+			return err
+		}
+		if err := savePNG(canvas, filepath.Join(dir, entry.Hash+".png")); err != nil {
+			return fmt.Errorf("failed to save badge %s: %w", entry.Hash, err)
+		}
+		badges[i] = canvas
+	}
+
+	sheet, err := contactSheet(entries, badges)
+	if err != nil {
+		return err
+	}
+	if err := savePNG(sheet, filepath.Join(dir, "contact-sheet.png")); err != nil {
+		return fmt.Errorf("failed to save contact sheet: %w", err)
+	}
+
+	return writeSheetHTML(filepath.Join(dir, "index.html"), entries)
+}
+
+// contactSheet lays badges out in a captioned grid
+func contactSheet(entries []SheetEntry, badges []image.Image) (*image.RGBA, error) {
+	columns := sheetColumns
+	if len(badges) < columns {
+		columns = len(badges)
+	}
+	rows := (len(badges) + columns - 1) / columns
+
+	cellW := Width + sheetPadding
+	cellH := sheetCaptionHeight + Height + sheetPadding
+	sheet := image.NewRGBA(image.Rect(0, 0, columns*cellW+sheetPadding, rows*cellH+sheetPadding))
+	drawRect(sheet, 0, 0, sheet.Bounds().Dx(), sheet.Bounds().Dy(), SheetBackground)
+
+	fonts, err := loadFonts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fonts: %w", err)
+	}
+	defer fonts.Large.Close()
+	defer fonts.Medium.Close()
+	defer fonts.StatValue.Close()
+	defer fonts.StatLabel.Close()
+
+	for i, b := range badges {
+		x := sheetPadding + (i%columns)*cellW
+		y := sheetPadding + (i/columns)*cellH
+		DrawTextSubtle(sheet, entries[i].caption(), x, y+sheetCaptionHeight-10, fonts.StatValue, DimWhiteColor)
+		dst := image.Rect(x, y+sheetCaptionHeight, x+b.Bounds().Dx(), y+sheetCaptionHeight+b.Bounds().Dy())
+		draw.Draw(sheet, dst, b, b.Bounds().Min, draw.Src)
+	}
+	return sheet, nil
+}
+
+var sheetHTML = template.Must(template.New("sheet").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>ContribEmblem preview</title>
+<style>
+body { background: #121216; color: #b4b4be; font-family: Inter, system-ui, sans-serif; margin: 16px; }
+figure { display: inline-block; margin: 0 16px 16px 0; }
+figcaption { margin-bottom: 6px; }
+code { color: #f5d96a; }
+img { display: block; width: 800px; height: 162px; }
+</style>
+</head>
+<body>
+{{range .}}<figure>
+<figcaption><code>{{.Hash}}</code> {{.Name}}</figcaption>
+<img src="{{.Hash}}.png" alt="{{.Name}}">
+</figure>
+{{end}}</body>
+</html>
+`))
+
+// writeSheetHTML writes a page showing each entry's badge
+func writeSheetHTML(path string, entries []SheetEntry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := sheetHTML.Execute(f, entries); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package badge
+
+import (
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateContactSheet(t *testing.T) {
+	tests := []struct {
+		name       string
+		entries    int
+		wantWidth  int
+		wantHeight int
+	}{
+		{"single emblem", 1, Width + 2*sheetPadding, sheetCaptionHeight + Height + 2*sheetPadding},
+		{"two per row", 2, 2*(Width+sheetPadding) + sheetPadding, sheetCaptionHeight + Height + 2*sheetPadding},
+		{"wraps to second row", 3, 2*(Width+sheetPadding) + sheetPadding, 2*(sheetCaptionHeight+Height+sheetPadding) + sheetPadding},
+	}
+
+	stats := &Stats{Username: "testuser", Commits: 150, PullRequests: 42, Issues: 18, Reviews: 67, Stars: 23}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var entries []SheetEntry
+			for i := 0; i < tt.entries; i++ {
+				entries = append(entries, SheetEntry{
+					Hash:       string(rune('1'+i)) + "000000000",
+					Name:       "Test <Emblem>",
+					EmblemPath: "testdata/test_emblem.jpg",
+				})
+			}
+
+			if err := GenerateContactSheet(entries, stats, Options{}, dir); err != nil {
+				t.Fatalf("GenerateContactSheet() error = %v", err)
+			}
+
+			f, err := os.Open(filepath.Join(dir, "contact-sheet.png"))
+			if err != nil {
+				t.Fatalf("contact sheet not written: %v", err)
+			}
+			defer f.Close()
+			cfg, _, err := image.DecodeConfig(f)
+			if err != nil {
+				t.Fatalf("failed to decode contact sheet: %v", err)
+			}
+			if cfg.Width != tt.wantWidth || cfg.Height != tt.wantHeight {
+				t.Errorf("contact sheet is %dx%d, want %dx%d", cfg.Width, cfg.Height, tt.wantWidth, tt.wantHeight)
+			}
+
+			for _, e := range entries {
+				if _, err := os.Stat(filepath.Join(dir, e.Hash+".png")); err != nil {
+					t.Errorf("badge for %s not written: %v", e.Hash, err)
+				}
+			}
+
+			html, err := os.ReadFile(filepath.Join(dir, "index.html"))
+			if err != nil {
+				t.Fatalf("index.html not written: %v", err)
+			}
+			if !strings.Contains(string(html), `src="1000000000.png"`) {
+				t.Error("index.html does not reference the badge images")
+			}
+			if strings.Contains(string(html), "<Emblem>") {
+				t.Error("index.html does not escape emblem names")
+			}
+		})
+	}
+}
+
+func TestGenerateContactSheetErrors(t *testing.T) {
+	stats := &Stats{Username: "testuser"}
+	if err := GenerateContactSheet(nil, stats, Options{}, t.TempDir()); err == nil {
+		t.Error("expected error for no entries")
+	}
+
+	missing := []SheetEntry{{Hash: "1", EmblemPath: "testdata/missing.jpg"}}
+	if err := GenerateContactSheet(missing, stats, Options{}, t.TempDir()); err == nil {
+		t.Error("expected error for missing emblem artwork")
+	}
+}
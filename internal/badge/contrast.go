@@ -0,0 +1,179 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// DefaultContrastTarget is WCAG AA for normal text
+const DefaultContrastTarget = 4.5
+
+// Adaptive contrast bounds. Floors keep the Destiny look on dark emblems;
+// ceilings stop bright emblems from turning into black boxes.
+const (
+	contrastPercentile = 0.9 // judge regions by their bright spots, not the mean
+	minOverlayAlpha    = 0
+	maxOverlayAlpha    = 160
+	minGradientAlpha   = 60
+	maxGradientAlpha   = 230
+	minStatBarAlpha    = 110
+	maxStatBarAlpha    = 235
+)
+
+// Text regions sampled for contrast, in badge coordinates
+var (
+	usernameRegion = image.Rect(130, accentHeight+marginTop, 450, accentHeight+marginTop+30)
+	powerRegion    = image.Rect(Width-260, accentHeight+marginTop, Width-marginX, accentHeight+marginTop+64)
+	statBarRegion  = image.Rect(0, Height-statBarHeight, Width, Height)
+)
+
+// contrastPlan holds the overlay strengths chosen for an emblem and the
+// contrast the text ends up with
+type contrastPlan struct {
+	Overlay  uint8 // full-badge darkening
+	Gradient uint8 // right-hand gradient at its darkest
+	StatBar  uint8
+	// ThinStroke gives stat bar text a 1px outline when the stat bar
+	// overlay already meets the target
+	ThinStroke bool
+	// Ratios is the white-text contrast per region after overlays, keyed
+	// "username", "power" and "stats"
+	Ratios map[string]float64
+}
+
+// classicContrast is the fixed overlay set used without adaptive contrast
+func classicContrast() contrastPlan {
+	return contrastPlan{Overlay: OverlayDark.A, Gradient: 150, StatBar: StatBarColor.A}
+}
+
+// planContrast picks the lightest overlays that give white text at least
+// target contrast against the emblem art under each text region
+func planContrast(art *image.RGBA, target float64) contrastPlan {
+	plan := contrastPlan{}
+
+	username := regionSamples(art, usernameRegion)
+	power := regionSamples(art, powerRegion)
+	stats := regionSamples(art, statBarRegion)
+
+	// The full-badge overlay is sized for the username, which has no other help
+	overlay := requiredAlpha(username, 0, target)
+	plan.Overlay = clampAlpha(overlay, minOverlayAlpha, maxOverlayAlpha)
+	base := float64(plan.Overlay) / 255
+
+	// The gradient is weakest at the power region's left edge
+	gradientFrac := float64(powerRegion.Min.X-int(float64(Width)*gradientStartX)) / float64(Width-int(float64(Width)*gradientStartX))
+	need := requiredAlpha(power, base, target)
+	plan.Gradient = clampAlpha(need/gradientFrac, minGradientAlpha, maxGradientAlpha)
+
+	plan.StatBar = clampAlpha(requiredAlpha(stats, base, target), minStatBarAlpha, maxStatBarAlpha)
+
+	// Stat text needs no heavy outline once the stat bar alone meets the
+	// target, i.e. unless the bar's alpha had to be capped
+	statBar := float64(plan.StatBar) / 255
+	plan.ThinStroke = percentileContrast(stats, 1-(1-base)*(1-statBar)) >= target
+	return plan
+}
+
+// measureContrast records the white-text contrast of each text region
+func (p *contrastPlan) measureContrast(canvas *image.RGBA) {
+	p.Ratios = map[string]float64{
+		"username": percentileContrast(regionSamples(canvas, usernameRegion), 0),
+		"power":    percentileContrast(regionSamples(canvas, powerRegion), 0),
+		"stats":    percentileContrast(regionSamples(canvas, statBarRegion), 0),
+	}
+}
+
+// regionSamples returns every other pixel of a region as opaque sRGB
+func regionSamples(img *image.RGBA, r image.Rectangle) []color.RGBA {
+	r = r.Intersect(img.Bounds())
+	var samples []color.RGBA
+	for y := r.Min.Y; y < r.Max.Y; y += 2 {
+		for x := r.Min.X; x < r.Max.X; x += 2 {
+			c := img.RGBAAt(x, y)
+			c.A = 255
+			samples = append(samples, c)
+		}
+	}
+	return samples
+}
+
+// requiredAlpha finds the extra black overlay alpha (0-1, on top of base)
+// that brings the region's bright spots to target contrast with white
+func requiredAlpha(samples []color.RGBA, base, target float64) float64 {
+	if percentileContrast(samples, base) >= target {
+		return 0
+	}
+	lo, hi := 0.0, 1.0
+	for i := 0; i < 12; i++ {
+		mid := (lo + hi) / 2
+		// Two stacked overlays darken by (1-base)(1-mid)
+		if percentileContrast(samples, 1-(1-base)*(1-mid)) >= target {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi
+}
+
+// percentileContrast is white's contrast against the region's bright-end
+// luminance after a black overlay of the given alpha
+func percentileContrast(samples []color.RGBA, alpha float64) float64 {
+	return (1 + 0.05) / (darkenedLuminance(samples, alpha) + 0.05)
+}
+
+// regionLuminance is the region's bright-end relative luminance
+func regionLuminance(samples []color.RGBA) float64 {
+	return darkenedLuminance(samples, 0)
+}
+
+// darkenedLuminance is the bright-end luminance after a black overlay
+func darkenedLuminance(samples []color.RGBA, alpha float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	lums := make([]float64, len(samples))
+	for i, c := range samples {
+		lums[i] = luminance(
+			float64(c.R)/255*(1-alpha),
+			float64(c.G)/255*(1-alpha),
+			float64(c.B)/255*(1-alpha),
+		)
+	}
+	sort.Float64s(lums)
+	return lums[int(contrastPercentile*float64(len(lums)-1))]
+}
+
+// ContrastRatio is the WCAG 2 contrast ratio between two colors (1 to 21)
+func ContrastRatio(a, b color.Color) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// relativeLuminance is the WCAG relative luminance of an opaque color
+func relativeLuminance(c color.Color) float64 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return luminance(float64(n.R)/255, float64(n.G)/255, float64(n.B)/255)
+}
+
+// luminance converts sRGB channels in [0,1] to relative luminance
+func luminance(r, g, b float64) float64 {
+	return 0.2126*linearize(r) + 0.7152*linearize(g) + 0.0722*linearize(b)
+}
+
+func linearize(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func clampAlpha(a float64, lo, hi uint8) uint8 {
+	v := math.Ceil(a * 255)
+	return uint8(math.Max(float64(lo), math.Min(float64(hi), v)))
+}
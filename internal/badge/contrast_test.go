@@ -0,0 +1,131 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func solidArt(c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 474, 96))
+	for y := 0; y < 96; y++ {
+		for x := 0; x < 474; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// stripedArt alternates bright and dark columns, like busy emblem art
+func stripedArt() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 474, 96))
+	for y := 0; y < 96; y++ {
+		for x := 0; x < 474; x++ {
+			c := color.RGBA{20, 20, 40, 255}
+			if (x/6)%2 == 0 {
+				c = color.RGBA{250, 240, 200, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b color.Color
+		want float64
+	}{
+		{"white on black", WhiteColor, BlackColor, 21},
+		{"black on white", BlackColor, WhiteColor, 21},
+		{"same color", DimWhiteColor, DimWhiteColor, 1},
+		{"mid gray", WhiteColor, color.RGBA{118, 118, 118, 255}, 4.54},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("ContrastRatio() = %.3f, want %.2f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdaptiveContrast(t *testing.T) {
+	stats := &Stats{Username: "testuser", Commits: 150, PullRequests: 42, Issues: 18, Reviews: 67, Stars: 23}
+
+	tests := []struct {
+		name       string
+		art        image.Image
+		target     float64
+		capped     bool // the target is out of reach of the overlay limits
+		thinStroke bool
+	}{
+		{"white art", solidArt(WhiteColor), DefaultContrastTarget, false, true},
+		{"bright yellow art", solidArt(color.RGBA{255, 230, 60, 255}), DefaultContrastTarget, false, true},
+		{"busy art", stripedArt(), DefaultContrastTarget, false, true},
+		{"dark art", solidArt(color.RGBA{15, 10, 30, 255}), DefaultContrastTarget, false, true},
+		{"stat bar capped", solidArt(WhiteColor), 21, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, plan, err := renderWithContrast(tt.art, stats, Options{ContrastTarget: tt.target})
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+			for region, ratio := range plan.Ratios {
+				if !tt.capped && ratio < tt.target {
+					t.Errorf("%s contrast %.2f below target %.1f", region, ratio, tt.target)
+				}
+			}
+			if plan.ThinStroke && plan.Ratios["stats"] < tt.target {
+				t.Errorf("thin stroke with stat bar contrast %.2f below target %.1f", plan.Ratios["stats"], tt.target)
+			}
+			if plan.ThinStroke != tt.thinStroke {
+				t.Errorf("ThinStroke = %v, want %v", plan.ThinStroke, tt.thinStroke)
+			}
+		})
+	}
+}
+
+func TestAdaptiveContrastLighterOnDarkArt(t *testing.T) {
+	stats := &Stats{Username: "testuser"}
+	opts := Options{ContrastTarget: DefaultContrastTarget}
+
+	_, dark, err := renderWithContrast(solidArt(color.RGBA{15, 10, 30, 255}), stats, opts)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	_, bright, err := renderWithContrast(solidArt(WhiteColor), stats, opts)
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+
+	if dark.Overlay >= bright.Overlay {
+		t.Errorf("dark art overlay %d not lighter than bright art %d", dark.Overlay, bright.Overlay)
+	}
+	if dark.Overlay >= classicContrast().Overlay {
+		t.Errorf("dark art overlay %d not lighter than classic %d", dark.Overlay, classicContrast().Overlay)
+	}
+	if dark.StatBar > bright.StatBar || dark.Gradient > bright.Gradient {
+		t.Errorf("dark art overlays %+v heavier than bright art %+v", dark, bright)
+	}
+}
+
+func TestClassicContrastUnchanged(t *testing.T) {
+	_, plan, err := renderWithContrast(solidArt(WhiteColor), &Stats{Username: "testuser"}, Options{})
+	if err != nil {
+		t.Fatalf("render failed: %v", err)
+	}
+	classic := classicContrast()
+	if plan.Overlay != classic.Overlay || plan.Gradient != classic.Gradient || plan.StatBar != classic.StatBar || plan.ThinStroke {
+		t.Errorf("without a target got %+v, want classic overlays", plan)
+	}
+	// Contrast is still measured for the fixed overlays
+	if plan.Ratios["stats"] == 0 {
+		t.Error("contrast not measured without a target")
+	}
+}
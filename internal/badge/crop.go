@@ -0,0 +1,122 @@
+package badge
+
+import (
+	"image"
+	"math"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// Entropy search settings: the art is sampled on a coarse grayscale grid and
+// the crop window slides across the spare axis in cropSteps positions
+const (
+	cropSampleWidth = 240
+	cropSteps       = 24
+	cropBins        = 32
+)
+
+// cropRect picks the region of the emblem art that fills the 800:162 badge.
+// An explicit offset wins over the strategy; unknown strategies center.
+func cropRect(src image.Image, strategy string, offset *config.CropOffset) image.Rectangle {
+	b := src.Bounds()
+	targetAspect := float64(Width) / float64(Height)
+
+	// Crop size matching the target aspect ratio
+	w, h := b.Dx(), b.Dy()
+	if float64(w)/float64(h) > targetAspect {
+		w = int(float64(h) * targetAspect)
+	} else {
+		h = int(float64(w) / targetAspect)
+	}
+
+	fx, fy := 0.5, 0.5
+	switch {
+	case offset != nil:
+		if offset.X != nil {
+			fx = *offset.X
+		}
+		if offset.Y != nil {
+			fy = *offset.Y
+		}
+	case strategy == config.CropLeft:
+		fx = 0
+	case strategy == config.CropEntropy:
+		fx, fy = entropyFocus(src, w, h)
+	}
+
+	// Truncate so the default center matches the classic (slack)/2 crop
+	x := b.Min.X + int(fx*float64(b.Dx()-w))
+	y := b.Min.Y + int(fy*float64(b.Dy()-h))
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// entropyFocus returns the crop position (as 0-1 fractions of the spare
+// width and height) whose window has the most varied luminance
+func entropyFocus(src image.Image, cropW, cropH int) (fx, fy float64) {
+	b := src.Bounds()
+	slackX, slackY := b.Dx()-cropW, b.Dy()-cropH
+	if slackX <= 0 && slackY <= 0 {
+		return 0.5, 0.5
+	}
+
+	// Coarse luminance grid keeps the search cheap on 1920x1080 art
+	scale := math.Min(1, float64(cropSampleWidth)/float64(b.Dx()))
+	gw := max(1, int(float64(b.Dx())*scale))
+	gh := max(1, int(float64(b.Dy())*scale))
+	grid := make([]uint8, gw*gh)
+	for gy := 0; gy < gh; gy++ {
+		for gx := 0; gx < gw; gx++ {
+			r, g, bl, _ := src.At(b.Min.X+gx*b.Dx()/gw, b.Min.Y+gy*b.Dy()/gh).RGBA()
+			grid[gy*gw+gx] = uint8((299*r + 587*g + 114*bl) / 1000 >> 8)
+		}
+	}
+
+	winW := max(1, int(float64(cropW)*scale))
+	winH := max(1, int(float64(cropH)*scale))
+
+	// Best position wins; ties keep the one nearest the center
+	best, bestDist := -1.0, math.Inf(1)
+	fx, fy = 0.5, 0.5
+	for step := 0; step <= cropSteps; step++ {
+		f := float64(step) / cropSteps
+		cx, cy := 0.5, 0.5
+		if slackX > 0 {
+			cx = f
+		} else {
+			cy = f
+		}
+
+		x0 := int(math.Round(cx * float64(gw-winW)))
+		y0 := int(math.Round(cy * float64(gh-winH)))
+		e := windowEntropy(grid, gw, x0, y0, winW, winH)
+		dist := math.Abs(f - 0.5)
+		if e > best+1e-9 || (math.Abs(e-best) <= 1e-9 && dist < bestDist) {
+			best, bestDist = e, dist
+			fx, fy = cx, cy
+		}
+	}
+	return fx, fy
+}
+
+// windowEntropy is the Shannon entropy of a luminance histogram over a
+// window of the grid
+func windowEntropy(grid []uint8, gw, x0, y0, w, h int) float64 {
+	var hist [cropBins]int
+	n := 0
+	for y := y0; y < y0+h; y++ {
+		for x := x0; x < x0+w; x++ {
+			hist[int(grid[y*gw+x])*cropBins/256]++
+			n++
+		}
+	}
+
+	var e float64
+	for _, c := range hist {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(n)
+		e -= p * math.Log2(p)
+	}
+	return e
+}
@@ -0,0 +1,96 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+func float(v float64) *float64 { return &v }
+
+// detailFixture is a flat wide image with a noisy checkerboard patch
+// starting at patchX, standing in for an emblem's motif
+func detailFixture(w, h, patchX, patchW int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.RGBA{40, 40, 60, 255}
+			if x >= patchX && x < patchX+patchW {
+				v := uint8((x*37 + y*91 + (x/3)*(y/3)*13) % 256)
+				c = color.RGBA{v, 255 - v, v / 2, 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCropRect(t *testing.T) {
+	// 1920x1080 detail art: the crop is 1920 wide, so only Y has slack
+	tall := image.NewRGBA(image.Rect(0, 0, 1920, 1080))
+	// 1600x162 banner: the crop is 800 wide, only X has slack
+	wide := image.NewRGBA(image.Rect(0, 0, 1600, 162))
+
+	tests := []struct {
+		name     string
+		src      image.Image
+		strategy string
+		offset   *config.CropOffset
+		want     image.Rectangle
+	}{
+		{"default centers tall art", tall, "", nil, image.Rect(0, 346, 1920, 734)},
+		{"center wide art", wide, config.CropCenter, nil, image.Rect(400, 0, 1200, 162)},
+		{"left wide art", wide, config.CropLeft, nil, image.Rect(0, 0, 800, 162)},
+		{"left keeps tall art centered", tall, config.CropLeft, nil, image.Rect(0, 346, 1920, 734)},
+		{"offset right", wide, config.CropLeft, &config.CropOffset{X: float(1)}, image.Rect(800, 0, 1600, 162)},
+		{"offset top", tall, "", &config.CropOffset{Y: float(0)}, image.Rect(0, 0, 1920, 388)},
+		{"offset unset axis centers", wide, "", &config.CropOffset{Y: float(1)}, image.Rect(400, 0, 1200, 162)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cropRect(tt.src, tt.strategy, tt.offset); got != tt.want {
+				t.Errorf("cropRect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCropRectEntropy(t *testing.T) {
+	tests := []struct {
+		name     string
+		patchX   int
+		wantMinX int
+		wantMaxX int
+	}{
+		{"motif on the right", 1300, 700, 800},
+		{"motif on the left", 50, 0, 100},
+		{"motif in the middle", 760, 300, 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := detailFixture(1600, 162, tt.patchX, 250)
+			got := cropRect(src, config.CropEntropy, nil)
+			if got.Dx() != 800 || got.Dy() != 162 {
+				t.Fatalf("crop is %dx%d, want 800x162", got.Dx(), got.Dy())
+			}
+			if got.Min.X < tt.wantMinX || got.Min.X > tt.wantMaxX {
+				t.Errorf("crop starts at x=%d, want %d-%d", got.Min.X, tt.wantMinX, tt.wantMaxX)
+			}
+			if got.Min.X > tt.patchX || got.Max.X < tt.patchX+250 {
+				t.Errorf("crop %v cuts the motif at x=%d-%d", got, tt.patchX, tt.patchX+250)
+			}
+		})
+	}
+}
+
+func TestCropRectEntropyFlat(t *testing.T) {
+	// No detail anywhere: ties resolve to the center
+	src := detailFixture(1600, 162, 0, 0)
+	if got := cropRect(src, config.CropEntropy, nil); got != image.Rect(400, 0, 1200, 162) {
+		t.Errorf("cropRect() = %v, want centered", got)
+	}
+}
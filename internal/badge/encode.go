@@ -0,0 +1,109 @@
+package badge
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// DefaultJPEGQuality is used when Encoding.Quality is unset
+const DefaultJPEGQuality = 90
+
+// Encoding tunes how the badge file is written. The format follows the
+// output path's extension: .png, or .jpg/.jpeg.
+type Encoding struct {
+	// Compression is the PNG zlib level (zero is png.DefaultCompression)
+	Compression png.CompressionLevel
+	// Colors, when 2-256, quantizes PNG output to a palette of that many
+	// colors; badges with smooth gradients dither
+	Colors int
+	// Quality is the JPEG quality, 1-100 (default DefaultJPEGQuality)
+	Quality int
+}
+
+// CompressionLevel maps a config compression name to its PNG level
+func CompressionLevel(name string) png.CompressionLevel {
+	switch name {
+	case config.CompressionBest:
+		return png.BestCompression
+	case config.CompressionFast:
+		return png.BestSpeed
+	case config.CompressionNone:
+		return png.NoCompression
+	default:
+		return png.DefaultCompression
+	}
+}
+
+// Format is a badge file format
+type Format string
+
+// Badge file formats; GIF and APNG are animated
+const (
+	FormatPNG  Format = "png"
+	FormatJPEG Format = "jpeg"
+	FormatGIF  Format = config.FormatGIF
+	FormatAPNG Format = config.FormatAPNG
+)
+
+// FormatFromPath picks a static format by extension: .png, .jpg or .jpeg
+func FormatFromPath(path string) (Format, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".png":
+		return FormatPNG, nil
+	case ".jpg", ".jpeg":
+		return FormatJPEG, nil
+	default:
+		return "", fmt.Errorf("unsupported badge format %q (use .png, .jpg or .jpeg)", ext)
+	}
+}
+
+// Encode writes a rendered badge to w as PNG or JPEG
+func Encode(w io.Writer, img image.Image, format Format, enc Encoding) error {
+	var err error
+	switch format {
+	case FormatPNG:
+		encoder := png.Encoder{CompressionLevel: enc.Compression}
+		if enc.Colors >= 2 {
+			img = quantizeImage(toRGBA(img), enc.Colors)
+		}
+		err = encoder.Encode(w, img)
+	case FormatJPEG:
+		quality := enc.Quality
+		if quality == 0 {
+			quality = DefaultJPEGQuality
+		}
+		err = jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	default:
+		return fmt.Errorf("unsupported badge format %q (use %s or %s)", format, FormatPNG, FormatJPEG)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+	return nil
+}
+
+// toRGBA returns img as *image.RGBA, converting only when needed
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba
+	}
+	rgba := image.NewRGBA(img.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	return rgba
+}
+
+// quantizeImage reduces img to a median-cut palette of up to n colors with
+// Floyd-Steinberg dithering
+func quantizeImage(img *image.RGBA, n int) *image.Paletted {
+	p := image.NewPaletted(img.Bounds(), medianCut([]*image.RGBA{img}, n))
+	draw.FloydSteinberg.Draw(p, img.Bounds(), img, img.Bounds().Min)
+	return p
+}
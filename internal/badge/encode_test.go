@@ -0,0 +1,101 @@
+package badge
+
+import (
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	stats := &Stats{Username: "testuser", Commits: 150, PullRequests: 42, Issues: 18, Reviews: 67, Stars: 23}
+	img, err := render(stripedArt(), stats, Options{})
+	if err != nil {
+		t.Fatalf("render() error = %v", err)
+	}
+	dir := t.TempDir()
+
+	save := func(name string, enc Encoding) (string, int64) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		format, err := FormatFromPath(name)
+		if err != nil {
+			t.Fatalf("FormatFromPath(%s) error = %v", name, err)
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := Encode(f, img, format, enc); err != nil {
+			t.Fatalf("Encode(%s) error = %v", name, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("stat %s: %v", name, err)
+		}
+		return path, info.Size()
+	}
+
+	_, defaultSize := save("default.png", Encoding{})
+	_, noneSize := save("none.png", Encoding{Compression: png.NoCompression})
+	_, bestSize := save("best.png", Encoding{Compression: png.BestCompression})
+	if noneSize <= defaultSize || bestSize > defaultSize {
+		t.Errorf("sizes none=%d default=%d best=%d, want none > default >= best", noneSize, defaultSize, bestSize)
+	}
+
+	t.Run("quantized PNG", func(t *testing.T) {
+		path, size := save("quantized.png", Encoding{Colors: 64})
+		if size >= defaultSize {
+			t.Errorf("quantized size %d, want under default %d", size, defaultSize)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		decoded, err := png.Decode(f)
+		if err != nil {
+			t.Fatalf("png.Decode() error = %v", err)
+		}
+		p, ok := decoded.(*image.Paletted)
+		if !ok {
+			t.Fatalf("decoded %T, want a paletted PNG", decoded)
+		}
+		if len(p.Palette) > 64 {
+			t.Errorf("palette has %d colors, want at most 64", len(p.Palette))
+		}
+	})
+
+	t.Run("JPEG by extension", func(t *testing.T) {
+		path, lowSize := save("low.jpg", Encoding{Quality: 40})
+		_, highSize := save("high.jpeg", Encoding{Quality: 95})
+		if lowSize >= highSize {
+			t.Errorf("quality 40 size %d, want under quality 95 size %d", lowSize, highSize)
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		decoded, err := jpeg.Decode(f)
+		if err != nil {
+			t.Fatalf("jpeg.Decode() error = %v", err)
+		}
+		if decoded.Bounds() != img.Bounds() {
+			t.Errorf("bounds = %v, want %v", decoded.Bounds(), img.Bounds())
+		}
+	})
+
+	t.Run("unsupported extension", func(t *testing.T) {
+		if _, err := FormatFromPath("badge.webp"); err == nil {
+			t.Error("expected an error for .webp")
+		}
+		if err := Encode(io.Discard, img, FormatGIF, Encoding{}); err == nil {
+			t.Error("expected Encode to reject animated formats")
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package badge
 
 import (
+	"bytes"
+	"context"
 	_ "embed"
 	"fmt"
 	"image"
@@ -14,6 +16,9 @@ import (
 	xdraw "golang.org/x/image/draw"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
 )
 
 //go:embed assets/fonts/Inter-Bold.ttf
@@ -27,13 +32,14 @@ const (
 	Height = 162 // Matches Destiny 2 emblem aspect ratio (474:96)
 
 	// Layout constants
-	marginX        = 20
-	marginTop      = 12
-	statBarHeight  = 44 // Increased from 36 to fit value-over-label layout
-	accentHeight   = 3
-	borderWidth    = 1
-	statDividerW   = 1
-	gradientStartX = 0.4 // Start gradient at 40% from left
+	marginX           = 20
+	marginTop         = 12
+	statBarHeight     = 44 // Increased from 36 to fit value-over-label layout
+	accentHeight      = 3
+	borderWidth       = 1
+	statDividerW      = 1
+	languageBarHeight = 3
+	gradientStartX    = 0.4 // Start gradient at 40% from left
 )
 
 var (
@@ -47,6 +53,8 @@ var (
 	StatBarColor    = color.RGBA{0, 0, 0, 170}      // Increased from 150 for better readability
 	DividerColor    = color.RGBA{255, 255, 255, 70} // Increased from 50 for better visibility
 	BorderColor     = color.RGBA{45, 45, 50, 255}
+	DeltaUpColor    = color.RGBA{123, 209, 140, 255} // Destiny uncommon green
+	DeltaDownColor  = color.RGBA{222, 110, 90, 255}
 	OverlayDark     = color.RGBA{0, 0, 0, 35}
 )
 
@@ -66,6 +74,72 @@ type Stats struct {
 	Issues       int
 	Reviews      int
 	Stars        int
+
+	// Consistency metrics derived from the contribution calendar
+	CurrentStreak   int
+	LongestStreak   int
+	ActiveDaysRatio float64
+
+	// Additional profile metrics
+	MergedPullRequests      int
+	RepositoriesContributed int
+	Followers               int
+	DiscussionAnswers       int
+	Gists                   int
+
+	// Languages, when set, renders a segmented language bar along the
+	// stat bar's top edge. Shares are normalized across the given entries.
+	Languages []LanguageShare
+
+	// Sparkline, when it has two or more values (oldest first), draws a mini
+	// activity chart right of the username in the accent color
+	Sparkline []float64
+
+	// Triumphs are earned achievements drawn as small icons beneath the
+	// username, in order
+	Triumphs []Triumph
+
+	// Deltas, when set, shows each cell's week-over-week change beneath its
+	// value, keyed by config metric key
+	Deltas map[string]float64
+
+	// Metrics lists the stat bar cells to render, by config metric key.
+	// Empty renders DefaultMetrics.
+	Metrics []string
+}
+
+// Options tunes badge rendering beyond the stats themselves
+type Options struct {
+	// PowerLevel configures the Power Level formula (nil uses the raw sum)
+	PowerLevel *config.PowerLevelConfig
+
+	// Tiers, when set, styles the accent, Power Level color and icon by
+	// light-level tier and shows the tier name. Nil keeps the classic gold.
+	Tiers []config.TierConfig
+
+	// Crop picks which part of the emblem art fills the badge: center
+	// (default), left or entropy. CropOffset, when set, overrides it.
+	Crop       string
+	CropOffset *config.CropOffset
+
+	// ContrastTarget, when set, fits the overlay, gradient and stat bar
+	// darkness to the emblem so white text reaches this WCAG contrast ratio
+	// (e.g. DefaultContrastTarget). Zero keeps the fixed overlays.
+	ContrastTarget float64
+
+	// Palette picks the accent line, divider and Power Level colors from the
+	// emblem art, keeping gold when no color contrasts well. Tiers win.
+	Palette bool
+
+	// Animation, when set, makes GenerateWithOptions write an animated GIF
+	// or APNG instead of the static PNG
+	Animation *Animation
+
+	// Encoding sets PNG compression and quantization or JPEG quality
+	Encoding Encoding
+
+	// frame is the animation state being rendered; nil is the static badge
+	frame *frame
 }
 
 // Generate creates badge image from emblem and stats
@@ -73,55 +147,96 @@ type Stats struct {
 // stats: GitHub contribution stats
 // outputPath: where to save badge PNG (badge.png)
 func Generate(emblemPath string, stats *Stats, outputPath string) error {
+	return GenerateWithOptions(emblemPath, stats, outputPath, Options{})
+}
+
+// GenerateWithOptions is Generate with rendering options. It's a file
+// wrapper around Render and Encode (or EncodeAnimation).
+func GenerateWithOptions(emblemPath string, stats *Stats, outputPath string, opts Options) error {
+	format := Format(opts.animationFormat())
+	if opts.Animation == nil {
+		var err error
+		if format, err = FormatFromPath(outputPath); err != nil {
+			return err
+		}
+	}
+
 	// Load emblem image
-	emblemImg, err := loadImage(emblemPath)
+	emblemImg, err := LoadEmblem(emblemPath)
 	if err != nil {
 		return fmt.Errorf("failed to load emblem: %w", err)
 	}
 
+	// Encode in memory so a failed render leaves the previous badge intact
+	var buf bytes.Buffer
+	ctx := context.Background()
+	if opts.Animation != nil {
+		err = EncodeAnimation(ctx, &buf, emblemImg, *stats, opts)
+	} else {
+		var img image.Image
+		if img, err = Render(ctx, emblemImg, *stats, opts); err == nil {
+			err = Encode(&buf, img, format, opts.Encoding)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputPath, buf.Bytes(), 0644)
+}
+
+// Render draws the badge over emblem artwork, for programs that embed
+// rendering without files. The static badge is always returned;
+// opts.Animation is ignored (see EncodeAnimation).
+func Render(ctx context.Context, emblem image.Image, stats Stats, opts Options) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	opts.Animation = nil
+	opts.frame = nil
+	return render(emblem, &stats, opts)
+}
+
+// LoadEmblem decodes emblem artwork (JPEG, PNG or GIF) from a file
+func LoadEmblem(path string) (image.Image, error) {
+	return loadImage(path)
+}
+
+// render draws the full badge over the emblem artwork
+func render(emblemImg image.Image, stats *Stats, opts Options) (*image.RGBA, error) {
+	canvas, _, err := renderWithContrast(emblemImg, stats, opts)
+	return canvas, err
+}
+
+// renderWithContrast is render that also reports the overlays chosen and
+// the resulting text contrast
+func renderWithContrast(emblemImg image.Image, stats *Stats, opts Options) (*image.RGBA, contrastPlan, error) {
 	// Create canvas
 	canvas := image.NewRGBA(image.Rect(0, 0, Width, Height))
 
-	// Phase 1: Scale and draw emblem as background with aspect-ratio-aware crop-to-fill
-	// Calculate target aspect ratio (800:162 = 4.94:1)
-	targetAspect := float64(Width) / float64(Height)
-	srcBounds := emblemImg.Bounds()
-	srcWidth := float64(srcBounds.Dx())
-	srcHeight := float64(srcBounds.Dy())
-	srcAspect := srcWidth / srcHeight
-
-	// Determine crop rectangle that matches target aspect ratio
-	var cropRect image.Rectangle
-	if srcAspect > targetAspect {
-		// Source is wider - crop horizontally (center crop)
-		newWidth := int(srcHeight * targetAspect)
-		offsetX := (srcBounds.Dx() - newWidth) / 2
-		cropRect = image.Rect(
-			srcBounds.Min.X+offsetX,
-			srcBounds.Min.Y,
-			srcBounds.Min.X+offsetX+newWidth,
-			srcBounds.Max.Y,
-		)
-	} else {
-		// Source is taller - crop vertically (center crop)
-		newHeight := int(srcWidth / targetAspect)
-		offsetY := (srcBounds.Dy() - newHeight) / 2
-		cropRect = image.Rect(
-			srcBounds.Min.X,
-			srcBounds.Min.Y+offsetY,
-			srcBounds.Max.X,
-			srcBounds.Min.Y+offsetY+newHeight,
-		)
-	}
+	// Phase 1: Scale and draw emblem as background, cropped to the badge's
+	// 800:162 aspect ratio by the configured strategy (center by default)
+	cropRect := cropRect(emblemImg, opts.Crop, opts.CropOffset)
 
 	// Scale the cropped region to fill the canvas using Catmull-Rom for sharper results
 	xdraw.CatmullRom.Scale(canvas, canvas.Bounds(), emblemImg, cropRect, xdraw.Over, nil)
 
+	// Sample the art's colors before the overlays darken it
+	var palette []paletteColor
+	if opts.Palette && opts.Tiers == nil {
+		palette = extractPalette(canvas, paletteClusters)
+	}
+
+	// Overlay strengths: fixed, or fitted to the art for a contrast target
+	plan := classicContrast()
+	if opts.ContrastTarget > 0 {
+		plan = planContrast(canvas, opts.ContrastTarget)
+	}
+
 	// Phase 2: Overall darken overlay for Destiny dark UI feel
-	drawRect(canvas, 0, 0, Width, Height, OverlayDark)
+	drawRect(canvas, 0, 0, Width, Height, color.RGBA{0, 0, 0, plan.Overlay})
 
 	// Phase 3: Horizontal gradient overlay (left transparent → right semi-opaque black)
-	drawHorizontalGradient(canvas, int(float64(Width)*gradientStartX), 0, Width, Height, color.RGBA{0, 0, 0, 150})
+	drawHorizontalGradient(canvas, int(float64(Width)*gradientStartX), 0, Width, Height, color.RGBA{0, 0, 0, plan.Gradient})
 
 	// Phase 3b: Bottom vignette (subtle bottom-up darkening above stat bar)
 	vignetteStartY := Height / 2           // start at vertical midpoint
@@ -130,17 +245,35 @@ func Generate(emblemPath string, stats *Stats, outputPath string) error {
 
 	// Phase 4: Semi-transparent stat bar across bottom
 	statBarY := Height - statBarHeight
-	drawRect(canvas, 0, statBarY, Width, statBarHeight, StatBarColor)
+	drawRect(canvas, 0, statBarY, Width, statBarHeight, color.RGBA{0, 0, 0, plan.StatBar})
+	plan.measureContrast(canvas)
 
-	// Stat bar top edge separator
-	StatBarEdgeColor := color.RGBA{255, 255, 255, 30} // very subtle white line
-	drawRect(canvas, 0, statBarY, Width, 1, StatBarEdgeColor)
+	// Stat bar top edge separator (or language bar when provided)
+	if len(stats.Languages) > 0 {
+		drawLanguageBar(canvas, 0, statBarY, Width, languageBarHeight, stats.Languages)
+	} else {
+		StatBarEdgeColor := color.RGBA{255, 255, 255, 30} // very subtle white line
+		drawRect(canvas, 0, statBarY, Width, 1, StatBarEdgeColor)
+	}
 
-	// Phase 5: Gold accent line at top
-	drawRect(canvas, 0, 0, Width, accentHeight, AccentColor)
+	// Calculate Power Level and its tier styling
+	powerLevel := powerlevel.Calculate(stats.metricValues(), opts.PowerLevel)
+	style := classicStyle()
+	if opts.Tiers != nil {
+		tier, _ := powerlevel.TierFor(powerLevel, opts.Tiers)
+		style = tierStyleFor(tier)
+	} else if palette != nil {
+		style = paletteStyle(style, palette, canvas)
+	}
+
+	// Phase 5: Accent line at top (gold unless tier- or palette-styled)
+	drawRect(canvas, 0, 0, Width, accentHeight, style.Accent)
 	// Accent glow (subtle bloom below the solid line)
-	AccentGlowColor := color.RGBA{206, 174, 51, 80} // translucent gold
+	AccentGlowColor := color.RGBA{style.Accent.R, style.Accent.G, style.Accent.B, 80}
 	drawRect(canvas, 0, accentHeight, Width, 1, AccentGlowColor)
+	if opts.frame != nil && opts.frame.Shimmer >= 0 {
+		drawShimmer(canvas, opts.frame.Shimmer)
+	}
 
 	// Phase 6: Border around entire badge
 	drawBorder(canvas, Width, Height, borderWidth, BorderColor)
@@ -148,16 +281,13 @@ func Generate(emblemPath string, stats *Stats, outputPath string) error {
 	// Load fonts
 	fonts, err := loadFonts()
 	if err != nil {
-		return fmt.Errorf("failed to load fonts: %w", err)
+		return nil, plan, fmt.Errorf("failed to load fonts: %w", err)
 	}
 	defer fonts.Large.Close()
 	defer fonts.Medium.Close()
 	defer fonts.StatValue.Close()
 	defer fonts.StatLabel.Close()
 
-	// Calculate Power Level
-	powerLevel := stats.Commits + stats.PullRequests + stats.Issues + stats.Reviews + stats.Stars
-
 	// Render username (positioned to the right of emblem icon, centered in left portion)
 	// In Destiny 2, usernames appear around 370-400px from left edge on 800px canvas
 	if stats.Username != "" {
@@ -166,8 +296,19 @@ func Generate(emblemPath string, stats *Stats, outputPath string) error {
 		DrawTextSubtle(canvas, strings.ToUpper(stats.Username), usernameX, usernameY, fonts.Medium, WhiteColor)
 	}
 
+	// Render triumph icons in a row beneath the username
+	drawTriumphs(canvas, stats.Triumphs, style.PowerLevel)
+
+	// Render activity sparkline in the gradient area
+	sparkRect := image.Rect(sparklineX, sparklineY, sparklineX+sparklineWidth, sparklineY+sparklineHeight)
+	drawSparkline(canvas, sparkRect, stats.Sparkline, style.Accent)
+
 	// Render Power Level (right-aligned with programmatic diamond icon)
+	// Animated frames count up to the final level, keeping its tier styling
 	powerText := fmt.Sprintf("%d", powerLevel)
+	if opts.frame != nil {
+		powerText = fmt.Sprintf("%d", opts.frame.powerLevel(powerLevel))
+	}
 
 	// Diamond sizing: proper diamond proportions (equal width and height)
 	// Increased slightly for better visibility next to 48pt text
@@ -187,53 +328,70 @@ func Generate(emblemPath string, stats *Stats, outputPath string) error {
 	diamondCX := powerX + diamondHalfW
 	diamondCY := powerY - 16 // adjust to visually center with number
 
-	// Draw diamond with outline for contrast (same 3-layer approach as text)
-	// Layer 1: shadow
-	drawDiamond(canvas, diamondCX+2, diamondCY+2, diamondHalfW+1, diamondHalfH+1, ShadowColor)
-	// Layer 2: black outline
-	drawDiamond(canvas, diamondCX, diamondCY, diamondHalfW+2, diamondHalfH+2, BlackColor)
-	// Layer 3: gold fill
-	drawDiamond(canvas, diamondCX, diamondCY, diamondHalfW, diamondHalfH, PowerLevelColor)
+	// Draw tier icon with outline for contrast (same 3-layer approach as text)
+	drawTierIcon(canvas, style.Icon, diamondCX, diamondCY, diamondHalfW, diamondHalfH, style.PowerLevel)
 
 	// Draw power level number after diamond with glow effect
-	DrawTextWithGlow(canvas, powerText, powerX+(diamondHalfW*2)+diamondGap, powerY, fonts.Large, PowerLevelColor, PowerLevelColor)
+	DrawTextWithGlow(canvas, powerText, powerX+(diamondHalfW*2)+diamondGap, powerY, fonts.Large, style.PowerLevel, style.PowerLevel)
 
-	// Render stats in stat bar (vertical value-over-label layout)
-	statLabels := []string{"COMMITS", "PRS", "ISSUES", "REVIEWS", "STARS"}
-	statValues := []int{stats.Commits, stats.PullRequests, stats.Issues, stats.Reviews, stats.Stars}
+	// Tier name right-aligned beneath the Power Level
+	if style.Label != "" {
+		label := strings.ToUpper(style.Label)
+		labelWidth := measureTextWithTracking(fonts.StatLabel, label, tierLabelTracking)
+		DrawTextWithTracking(canvas, label, Width-marginX-labelWidth, powerY+18, fonts.StatLabel, style.PowerLevel, tierLabelTracking)
+	}
 
-	cellWidth := Width / 5
+	// Render stats in stat bar (vertical value-over-label layout)
+	// Dark art gets the lighter outline
+	drawStatText := DrawTextWithOutline
+	if plan.ThinStroke {
+		drawStatText = DrawTextSubtle
+	}
+	cells := stats.statCells()
+	cellWidth := Width / len(cells)
 
-	for i := 0; i < 5; i++ {
+	for i, cell := range cells {
 		// Draw vertical divider (except before first stat)
 		if i > 0 {
 			dividerX := i * cellWidth
-			drawRect(canvas, dividerX, statBarY, statDividerW, statBarHeight, DividerColor)
+			drawRect(canvas, dividerX, statBarY, statDividerW, statBarHeight, style.Divider)
 		}
 
-		label := statLabels[i]
-		value := FormatNumber(statValues[i])
-
 		// Center value horizontally in cell
-		valueWidth := measureText(fonts.StatValue, value)
+		valueWidth := measureText(fonts.StatValue, cell.Value)
 		cellCenterX := i*cellWidth + cellWidth/2
 		valueX := cellCenterX - valueWidth/2
 
 		// Center label horizontally in cell
-		labelWidth := measureText(fonts.StatLabel, label)
+		labelWidth := measureText(fonts.StatLabel, cell.Label)
 		labelX := cellCenterX - labelWidth/2
 
 		// Value on upper line: 18px from stat bar top
-		valueY := statBarY + 18
-		DrawTextWithOutline(canvas, value, valueX, valueY, fonts.StatValue, WhiteColor)
+		// (tightened to three lines when deltas are shown)
+		valueY, labelY := statBarY+18, statBarY+36
+		if stats.Deltas != nil {
+			valueY, labelY = statBarY+15, statBarY+40
+		}
+		drawStatText(canvas, cell.Value, valueX, valueY, fonts.StatValue, WhiteColor)
+
+		// Week-over-week delta between value and label
+		if stats.Deltas != nil && cell.Delta != "" {
+			deltaColor := DeltaUpColor
+			switch {
+			case strings.HasPrefix(cell.Delta, "-"):
+				deltaColor = DeltaDownColor
+			case strings.HasPrefix(cell.Delta, NoChange):
+				deltaColor = DimWhiteColor
+			}
+			deltaX := cellCenterX - measureText(fonts.StatLabel, cell.Delta)/2
+			drawStatText(canvas, cell.Delta, deltaX, statBarY+27, fonts.StatLabel, deltaColor)
+		}
 
 		// Label on lower line: 36px from stat bar top
-		labelY := statBarY + 36
-		DrawTextWithOutline(canvas, label, labelX, labelY, fonts.StatLabel, DimWhiteColor)
+		drawStatText(canvas, cell.Label, labelX, labelY, fonts.StatLabel, DimWhiteColor)
 	}
 
-	// Save PNG
-	return savePNG(canvas, outputPath)
+	return canvas, plan, nil
 }
 
 func loadImage(path string) (image.Image, error) {
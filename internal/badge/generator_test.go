@@ -1,8 +1,12 @@
 package badge
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"image"
 	_ "image/png"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -82,6 +86,53 @@ func TestGenerate(t *testing.T) {
 			wantHeight:  Height,
 			description: "Should handle large stat values",
 		},
+		{
+			name:       "language bar",
+			emblemPath: "testdata/test_emblem.jpg",
+			stats: &Stats{
+				Username: "polyglot",
+				Commits:  300,
+				Languages: []LanguageShare{
+					{Name: "Go", Color: "#00ADD8", Share: 0.6},
+					{Name: "TypeScript", Color: "#3178c6", Share: 0.3},
+					{Name: "Shell", Color: "#89e051", Share: 0.1},
+				},
+			},
+			wantErr:     false,
+			wantWidth:   Width,
+			wantHeight:  Height,
+			description: "Should render language bar along the stat bar",
+		},
+		{
+			name:       "week-over-week deltas",
+			emblemPath: "testdata/test_emblem.jpg",
+			stats: &Stats{
+				Username: "weekly",
+				Commits:  300,
+				Reviews:  40,
+				Deltas:   map[string]float64{"commits": 42, "reviews": -1},
+			},
+			wantErr:     false,
+			wantWidth:   Width,
+			wantHeight:  Height,
+			description: "Should render deltas beneath stat values",
+		},
+		{
+			name:       "triumph icons",
+			emblemPath: "testdata/test_emblem.jpg",
+			stats: &Stats{
+				Username: "decorated",
+				Reviews:  150,
+				Triumphs: []Triumph{
+					{Name: "100 Reviews", Icon: "diamond-inset"},
+					{Name: "First 1K Stars", Icon: "star"},
+				},
+			},
+			wantErr:     false,
+			wantWidth:   Width,
+			wantHeight:  Height,
+			description: "Should render triumph icons beneath the username",
+		},
 		{
 			name:       "missing emblem file",
 			emblemPath: "testdata/nonexistent.jpg",
@@ -147,3 +198,64 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderAndEncode(t *testing.T) {
+	emblem, err := LoadEmblem("testdata/test_emblem.jpg")
+	if err != nil {
+		t.Fatalf("LoadEmblem() error = %v", err)
+	}
+	stats := Stats{Username: "testuser", Commits: 150, PullRequests: 42, Issues: 18, Reviews: 67, Stars: 23}
+
+	img, err := Render(context.Background(), emblem, stats, Options{})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if img.Bounds() != image.Rect(0, 0, Width, Height) {
+		t.Errorf("Render() bounds = %v, want %dx%d", img.Bounds(), Width, Height)
+	}
+
+	// Encoding in memory matches what Generate writes to disk
+	var buf bytes.Buffer
+	if err := Encode(&buf, img, FormatPNG, Encoding{}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "badge.png")
+	if err := Generate("testdata/test_emblem.jpg", &stats, path); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	onDisk, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), onDisk) {
+		t.Error("Encode() output differs from Generate()")
+	}
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := Render(ctx, emblem, stats, Options{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("Render() error = %v, want context.Canceled", err)
+		}
+		opts := Options{Animation: &Animation{Frames: 4}}
+		if err := EncodeAnimation(ctx, io.Discard, emblem, stats, opts); !errors.Is(err, context.Canceled) {
+			t.Errorf("EncodeAnimation() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("animation without options", func(t *testing.T) {
+		if err := EncodeAnimation(context.Background(), io.Discard, emblem, stats, Options{}); err == nil {
+			t.Error("expected an error without opts.Animation")
+		}
+	})
+
+	t.Run("unsupported output leaves no file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "badge.webp")
+		if err := Generate("testdata/test_emblem.jpg", &stats, path); err == nil {
+			t.Error("expected an error for .webp output")
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Error("no file should be created for an unsupported format")
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package badge
+
+import (
+	"image/color"
+	"image/draw"
+	"strconv"
+	"strings"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+// LanguageShare is one segment of the language bar
+type LanguageShare struct {
+	Name  string
+	Color string // hex color such as "#00ADD8"; empty uses a neutral grey
+	Share float64
+}
+
+// TopLanguages converts the first n fetched languages into language bar
+// segments (config.DefaultTopLanguages when n is 0)
+func TopLanguages(languages []github.Language, n int) []LanguageShare {
+	if n <= 0 {
+		n = config.DefaultTopLanguages
+	}
+	if len(languages) > n {
+		languages = languages[:n]
+	}
+
+	shares := make([]LanguageShare, 0, len(languages))
+	for _, lang := range languages {
+		shares = append(shares, LanguageShare{Name: lang.Name, Color: lang.Color, Share: lang.Share})
+	}
+	return shares
+}
+
+// unknownLanguageColor is used for languages GitHub assigns no color
+var unknownLanguageColor = color.RGBA{140, 140, 150, 255}
+
+// drawLanguageBar draws proportional colored segments across width.
+// The last segment absorbs rounding so the bar always spans the full width.
+func drawLanguageBar(dst draw.Image, x, y, width, height int, languages []LanguageShare) {
+	var total float64
+	last := -1
+	for i, lang := range languages {
+		if lang.Share > 0 {
+			total += lang.Share
+			last = i
+		}
+	}
+	if total == 0 {
+		return
+	}
+
+	cursor := x
+	for i, lang := range languages {
+		if lang.Share <= 0 {
+			continue
+		}
+		segW := int(float64(width) * lang.Share / total)
+		if i == last {
+			segW = x + width - cursor
+		}
+
+		col, ok := parseHexColor(lang.Color)
+		if !ok {
+			col = unknownLanguageColor
+		}
+		drawRect(dst, cursor, y, segW, height, col)
+		cursor += segW
+	}
+}
+
+// parseHexColor parses "#RRGGBB" or "#RGB" (leading # optional) into an opaque color
+func parseHexColor(s string) (color.RGBA, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return color.RGBA{}, false
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.RGBA{}, false
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}, true
+}
@@ -0,0 +1,50 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input  string
+		want   color.RGBA
+		wantOK bool
+	}{
+		{"#00ADD8", color.RGBA{0x00, 0xAD, 0xD8, 255}, true},
+		{"3572A5", color.RGBA{0x35, 0x72, 0xA5, 255}, true},
+		{"#fff", color.RGBA{255, 255, 255, 255}, true},
+		{"", color.RGBA{}, false},
+		{"#GGGGGG", color.RGBA{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseHexColor(tt.input)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseHexColor(%q) = %v, %v; want %v, %v", tt.input, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDrawLanguageBar(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 100, 3))
+	languages := []LanguageShare{
+		{Name: "Go", Color: "#FF0000", Share: 0.3},
+		{Name: "Rust", Color: "#0000FF", Share: 0.1},
+		{Name: "Mystery", Color: "", Share: 0.1},
+	}
+
+	drawLanguageBar(img, 0, 0, 100, 3, languages)
+
+	// Shares are normalized: Go 60px, Rust 20px, Mystery fills the rest
+	if c := img.RGBAAt(59, 1); c != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("expected Go segment at x=59, got %v", c)
+	}
+	if c := img.RGBAAt(60, 1); c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("expected Rust segment at x=60, got %v", c)
+	}
+	if c := img.RGBAAt(99, 1); c != unknownLanguageColor {
+		t.Errorf("expected neutral segment at right edge, got %v", c)
+	}
+}
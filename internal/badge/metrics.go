@@ -0,0 +1,156 @@
+package badge
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+)
+
+// DefaultMetrics are the stat bar cells rendered when Stats.Metrics is empty
+var DefaultMetrics = []string{
+	config.MetricCommits,
+	config.MetricPullRequests,
+	config.MetricIssues,
+	config.MetricReviews,
+	config.MetricStars,
+}
+
+// FromGitHub copies fetched GitHub stats into badge stats. Decorations
+// (metrics, languages, sparkline, triumphs, deltas) are left to the caller.
+func FromGitHub(username string, stats *github.Stats) *Stats {
+	return &Stats{
+		Username:        username,
+		Commits:         stats.Commits,
+		PullRequests:    stats.PullRequests,
+		Issues:          stats.Issues,
+		Reviews:         stats.Reviews,
+		Stars:           stats.StarsReceived,
+		CurrentStreak:   stats.CurrentStreak,
+		LongestStreak:   stats.LongestStreak,
+		ActiveDaysRatio: stats.ActiveDaysRatio,
+
+		MergedPullRequests:      stats.MergedPullRequests,
+		RepositoriesContributed: stats.RepositoriesContributed,
+		Followers:               stats.Followers,
+		DiscussionAnswers:       stats.DiscussionAnswers,
+		Gists:                   stats.Gists,
+	}
+}
+
+// StatCell is a single value-over-label cell in the stat bar
+type StatCell struct {
+	Label string
+	Value string
+	Delta string // week-over-week change, e.g. "+42"; empty when unknown
+}
+
+// statCell returns the stat bar cell for a metric key
+// ok is false for unknown keys so callers can skip them
+func (s *Stats) statCell(key string) (cell StatCell, ok bool) {
+	switch key {
+	case config.MetricCommits:
+		return StatCell{Label: "COMMITS", Value: FormatNumber(s.Commits)}, true
+	case config.MetricPullRequests:
+		return StatCell{Label: "PRS", Value: FormatNumber(s.PullRequests)}, true
+	case config.MetricIssues:
+		return StatCell{Label: "ISSUES", Value: FormatNumber(s.Issues)}, true
+	case config.MetricReviews:
+		return StatCell{Label: "REVIEWS", Value: FormatNumber(s.Reviews)}, true
+	case config.MetricStars:
+		return StatCell{Label: "STARS", Value: FormatNumber(s.Stars)}, true
+	case config.MetricCurrentStreak:
+		return StatCell{Label: "STREAK", Value: FormatNumber(s.CurrentStreak)}, true
+	case config.MetricLongestStreak:
+		return StatCell{Label: "BEST STREAK", Value: FormatNumber(s.LongestStreak)}, true
+	case config.MetricActiveDaysRatio:
+		return StatCell{Label: "ACTIVE DAYS", Value: fmt.Sprintf("%d%%", int(math.Round(s.ActiveDaysRatio*100)))}, true
+	case config.MetricMergedPRs:
+		return StatCell{Label: "MERGED", Value: FormatNumber(s.MergedPullRequests)}, true
+	case config.MetricReposContrib:
+		return StatCell{Label: "REPOS", Value: FormatNumber(s.RepositoriesContributed)}, true
+	case config.MetricFollowers:
+		return StatCell{Label: "FOLLOWERS", Value: FormatNumber(s.Followers)}, true
+	case config.MetricDiscussions:
+		return StatCell{Label: "ANSWERS", Value: FormatNumber(s.DiscussionAnswers)}, true
+	case config.MetricGists:
+		return StatCell{Label: "GISTS", Value: FormatNumber(s.Gists)}, true
+	}
+	return StatCell{}, false
+}
+
+// statCells resolves Stats.Metrics into renderable cells, falling back to
+// DefaultMetrics when no known metric is selected
+func (s *Stats) statCells() []StatCell {
+	var cells []StatCell
+	for _, key := range s.Metrics {
+		if cell, ok := s.statCell(key); ok {
+			cells = append(cells, s.withDelta(cell, key))
+		}
+	}
+	if len(cells) > 0 {
+		return cells
+	}
+
+	for _, key := range DefaultMetrics {
+		cell, _ := s.statCell(key)
+		cells = append(cells, s.withDelta(cell, key))
+	}
+	return cells
+}
+
+// NoChange is the delta text of a metric that didn't change (after
+// rounding to what the cell shows)
+const NoChange = "±0"
+
+// withDelta fills in the cell's delta text from Stats.Deltas
+func (s *Stats) withDelta(cell StatCell, key string) StatCell {
+	delta, ok := s.Deltas[key]
+	if !ok {
+		return cell
+	}
+
+	// Round to the cell's precision first, so -0.004 doesn't show as "-0"
+	unit := ""
+	if key == config.MetricActiveDaysRatio {
+		delta, unit = delta*100, "%"
+	}
+	n := int(math.Round(delta))
+	switch {
+	case n == 0:
+		cell.Delta = NoChange + unit
+	case n < 0:
+		cell.Delta = "-" + FormatNumber(-n) + unit
+	default:
+		cell.Delta = "+" + FormatNumber(n) + unit
+	}
+	return cell
+}
+
+// gitHub is the inverse of FromGitHub, for code shared with fetched stats
+func (s *Stats) gitHub() *github.Stats {
+	return &github.Stats{
+		Commits:         s.Commits,
+		PullRequests:    s.PullRequests,
+		Issues:          s.Issues,
+		Reviews:         s.Reviews,
+		StarsReceived:   s.Stars,
+		CurrentStreak:   s.CurrentStreak,
+		LongestStreak:   s.LongestStreak,
+		ActiveDaysRatio: s.ActiveDaysRatio,
+
+		MergedPullRequests:      s.MergedPullRequests,
+		RepositoriesContributed: s.RepositoriesContributed,
+		Followers:               s.Followers,
+		DiscussionAnswers:       s.DiscussionAnswers,
+		Gists:                   s.Gists,
+	}
+}
+
+// metricValues maps the stats to metric values keyed by config metric key,
+// using the same mapping as the power-level command
+func (s *Stats) metricValues() map[string]float64 {
+	return powerlevel.FromStats(s.gitHub())
+}
@@ -0,0 +1,105 @@
+package badge
+
+import (
+	"maps"
+	"reflect"
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+)
+
+func TestStatCells(t *testing.T) {
+	stats := &Stats{
+		Commits:         1200,
+		PullRequests:    42,
+		Issues:          7,
+		Reviews:         99,
+		Stars:           5,
+		CurrentStreak:   12,
+		LongestStreak:   40,
+		ActiveDaysRatio: 0.874,
+		Followers:       2500,
+		Gists:           3,
+	}
+
+	// Default metrics when none selected
+	cells := stats.statCells()
+	if len(cells) != len(DefaultMetrics) {
+		t.Fatalf("expected %d default cells, got %d", len(DefaultMetrics), len(cells))
+	}
+	if cells[0] != (StatCell{Label: "COMMITS", Value: "1.2K"}) {
+		t.Errorf("unexpected first default cell: %+v", cells[0])
+	}
+
+	// Selected metrics, unknown keys skipped
+	stats.Metrics = []string{
+		config.MetricCurrentStreak, "bogus", config.MetricLongestStreak,
+		config.MetricActiveDaysRatio, config.MetricFollowers, config.MetricGists,
+	}
+	cells = stats.statCells()
+	want := []StatCell{
+		{Label: "STREAK", Value: "12"},
+		{Label: "BEST STREAK", Value: "40"},
+		{Label: "ACTIVE DAYS", Value: "87%"},
+		{Label: "FOLLOWERS", Value: "2.5K"},
+		{Label: "GISTS", Value: "3"},
+	}
+	if len(cells) != len(want) {
+		t.Fatalf("expected %d cells, got %d: %+v", len(want), len(cells), cells)
+	}
+	for i := range want {
+		if cells[i] != want[i] {
+			t.Errorf("cell %d = %+v, want %+v", i, cells[i], want[i])
+		}
+	}
+}
+
+func TestStatCellsDeltas(t *testing.T) {
+	stats := &Stats{
+		Commits: 500,
+		Stars:   10,
+		Deltas: map[string]float64{
+			config.MetricCommits:         42,
+			config.MetricStars:           -3,
+			config.MetricActiveDaysRatio: 0.021,
+			config.MetricReviews:         0,
+			config.MetricFollowers:       -0.004,
+			config.MetricGists:           0.4,
+		},
+		Metrics: []string{
+			config.MetricCommits, config.MetricStars, config.MetricActiveDaysRatio, config.MetricIssues,
+			config.MetricReviews, config.MetricFollowers, config.MetricGists,
+		},
+	}
+
+	cells := stats.statCells()
+	want := []string{"+42", "-3", "+2%", "", "±0", "±0", "±0"}
+	for i, delta := range want {
+		if cells[i].Delta != delta {
+			t.Errorf("cell %d delta = %q, want %q", i, cells[i].Delta, delta)
+		}
+	}
+}
+
+func TestMetricValuesMatchPowerLevel(t *testing.T) {
+	// Give every numeric field a distinct value so a field dropped by
+	// FromGitHub or gitHub shows up as a mismatch
+	var gh github.Stats
+	v := reflect.ValueOf(&gh).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch f := v.Field(i); f.Kind() {
+		case reflect.Int:
+			f.SetInt(int64(i + 1))
+		case reflect.Float64:
+			f.SetFloat(float64(i+1) / 100)
+		}
+	}
+
+	got := FromGitHub("user", &gh).metricValues()
+	want := powerlevel.FromStats(&gh)
+	if !maps.Equal(got, want) {
+		t.Errorf("metricValues() = %v, want %v", got, want)
+	}
+}
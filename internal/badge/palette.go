@@ -0,0 +1,208 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// Palette extraction settings
+const (
+	paletteClusters   = 5
+	paletteIterations = 10
+	paletteStride     = 4    // sample every 4th pixel in each direction
+	paletteMinWeight  = 0.05 // ignore colors covering under 5% of the art
+	paletteMinSat     = 0.25 // grays and near-grays keep the gold
+	paletteMinLight   = 0.55
+	paletteMaxLight   = 0.75
+	// WCAG 3:1 for large text and UI components
+	paletteMinContrast = 3.0
+	// How far the Power Level fill is tinted from the accent toward white
+	paletteFillTint = 0.45
+)
+
+// paletteColor is a cluster of similar colors in the emblem art
+type paletteColor struct {
+	Color  color.RGBA
+	Weight float64 // share of sampled pixels
+}
+
+// extractPalette clusters the art's colors with k-means, most common first.
+// Initial centers are spread across the luminance range so results are
+// deterministic.
+func extractPalette(img *image.RGBA, k int) []paletteColor {
+	b := img.Bounds()
+	var samples [][3]float64
+	for y := b.Min.Y; y < b.Max.Y; y += paletteStride {
+		for x := b.Min.X; x < b.Max.X; x += paletteStride {
+			c := img.RGBAAt(x, y)
+			samples = append(samples, [3]float64{float64(c.R), float64(c.G), float64(c.B)})
+		}
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	if k > len(samples) {
+		k = len(samples)
+	}
+
+	sorted := make([][3]float64, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return lumaOf(sorted[i]) < lumaOf(sorted[j]) })
+	centers := make([][3]float64, k)
+	for i := range centers {
+		centers[i] = sorted[(2*i+1)*len(sorted)/(2*k)]
+	}
+
+	assign := make([]int, len(samples))
+	for iter := 0; iter < paletteIterations; iter++ {
+		sums := make([][3]float64, k)
+		counts := make([]int, k)
+		for i, s := range samples {
+			best, bestDist := 0, math.Inf(1)
+			for c, center := range centers {
+				d := sq(s[0]-center[0]) + sq(s[1]-center[1]) + sq(s[2]-center[2])
+				if d < bestDist {
+					best, bestDist = c, d
+				}
+			}
+			assign[i] = best
+			counts[best]++
+			for ch := 0; ch < 3; ch++ {
+				sums[best][ch] += s[ch]
+			}
+		}
+		for c := range centers {
+			if counts[c] == 0 {
+				continue
+			}
+			for ch := 0; ch < 3; ch++ {
+				centers[c][ch] = sums[c][ch] / float64(counts[c])
+			}
+		}
+	}
+
+	counts := make([]int, k)
+	for _, a := range assign {
+		counts[a]++
+	}
+	var palette []paletteColor
+	for c, center := range centers {
+		if counts[c] == 0 {
+			continue
+		}
+		palette = append(palette, paletteColor{
+			Color:  color.RGBA{uint8(math.Round(center[0])), uint8(math.Round(center[1])), uint8(math.Round(center[2])), 255},
+			Weight: float64(counts[c]) / float64(len(samples)),
+		})
+	}
+	sort.SliceStable(palette, func(i, j int) bool { return palette[i].Weight > palette[j].Weight })
+	return palette
+}
+
+// pickAccent chooses the most vivid sizable palette color, lightened until
+// it stands out against the dark badge. ok is false when no color qualifies.
+func pickAccent(palette []paletteColor) (accent color.RGBA, ok bool) {
+	bestScore := 0.0
+	for _, p := range palette {
+		if p.Weight < paletteMinWeight {
+			continue
+		}
+		h, s, _ := toHSL(p.Color)
+		if s < paletteMinSat {
+			continue
+		}
+		score := s * math.Sqrt(p.Weight)
+		if score <= bestScore {
+			continue
+		}
+
+		// Lift dark hues so the accent line reads on a near-black badge
+		for l := paletteMinLight; l <= paletteMaxLight+1e-9; l += 0.05 {
+			c := fromHSL(h, math.Max(s, 0.5), l)
+			if ContrastRatio(c, BlackColor) >= paletteMinContrast {
+				accent, ok, bestScore = c, true, score
+				break
+			}
+		}
+	}
+	return accent, ok
+}
+
+// paletteStyle restyles the accent line, stat dividers and Power Level from
+// the emblem's palette. The classic gold stays when the art has no usable
+// color or the Power Level would lose contrast against its background.
+func paletteStyle(style tierStyle, palette []paletteColor, canvas *image.RGBA) tierStyle {
+	accent, ok := pickAccent(palette)
+	if !ok {
+		return style
+	}
+
+	fill := blend(accent, WhiteColor, paletteFillTint)
+	bg := regionLuminance(regionSamples(canvas, powerRegion))
+	if (relativeLuminance(fill)+0.05)/(bg+0.05) < paletteMinContrast {
+		return style
+	}
+
+	style.Accent = accent
+	style.PowerLevel = fill
+	style.Divider = color.NRGBA{accent.R, accent.G, accent.B, 110}
+	return style
+}
+
+// toHSL converts an opaque color to hue (0-360), saturation and lightness
+func toHSL(c color.RGBA) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	l = (maxC + minC) / 2
+	d := maxC - minC
+	if d == 0 {
+		return 0, 0, l
+	}
+
+	s = d / (1 - math.Abs(2*l-1))
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h, s, l
+}
+
+// fromHSL converts hue (0-360), saturation and lightness to an opaque color
+func fromHSL(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	to8 := func(v float64) uint8 { return uint8(math.Round((v + m) * 255)) }
+	return color.RGBA{to8(r), to8(g), to8(b), 255}
+}
+
+func lumaOf(c [3]float64) float64 { return 0.299*c[0] + 0.587*c[1] + 0.114*c[2] }
+
+func sq(v float64) float64 { return v * v }
@@ -0,0 +1,174 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// splitArt fills the left part of the art with one color and the rest with another
+func splitArt(left, right color.RGBA, share float64) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 474, 96))
+	split := int(share * 474)
+	for y := 0; y < 96; y++ {
+		for x := 0; x < 474; x++ {
+			if x < split {
+				img.SetRGBA(x, y, left)
+			} else {
+				img.SetRGBA(x, y, right)
+			}
+		}
+	}
+	return img
+}
+
+func TestExtractPalette(t *testing.T) {
+	crimson := color.RGBA{150, 20, 40, 255}
+	navy := color.RGBA{15, 20, 45, 255}
+	palette := extractPalette(splitArt(navy, crimson, 0.7), paletteClusters)
+
+	if len(palette) < 2 {
+		t.Fatalf("extractPalette() returned %d colors, want at least 2", len(palette))
+	}
+	if palette[0].Color != navy {
+		t.Errorf("dominant color = %v, want %v", palette[0].Color, navy)
+	}
+
+	var total float64
+	found := false
+	for _, p := range palette {
+		total += p.Weight
+		if p.Color == crimson {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("palette %v is missing %v", palette, crimson)
+	}
+	if total < 0.999 || total > 1.001 {
+		t.Errorf("weights sum to %.3f, want 1", total)
+	}
+}
+
+func TestPickAccent(t *testing.T) {
+	tests := []struct {
+		name    string
+		palette []paletteColor
+		wantOK  bool
+		wantHue func(h float64) bool
+	}{
+		{
+			name:    "vivid crimson wins over dominant navy",
+			palette: []paletteColor{{color.RGBA{15, 20, 45, 255}, 0.7}, {color.RGBA{150, 20, 40, 255}, 0.3}},
+			wantOK:  true,
+			wantHue: func(h float64) bool { return h > 340 || h < 10 },
+		},
+		{
+			name:    "grays keep gold",
+			palette: []paletteColor{{color.RGBA{40, 40, 40, 255}, 0.6}, {color.RGBA{180, 180, 175, 255}, 0.4}},
+		},
+		{
+			name:    "tiny specks of color are ignored",
+			palette: []paletteColor{{color.RGBA{60, 60, 60, 255}, 0.98}, {color.RGBA{0, 200, 80, 255}, 0.02}},
+		},
+		{
+			name:   "empty palette",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accent, ok := pickAccent(tt.palette)
+			if ok != tt.wantOK {
+				t.Fatalf("pickAccent() ok = %v, want %v (accent %v)", ok, tt.wantOK, accent)
+			}
+			if !ok {
+				return
+			}
+			if h, _, _ := toHSL(accent); !tt.wantHue(h) {
+				t.Errorf("accent %v has hue %.0f", accent, h)
+			}
+			if r := ContrastRatio(accent, BlackColor); r < paletteMinContrast {
+				t.Errorf("accent %v contrast on black = %.2f, want >= %.1f", accent, r, paletteMinContrast)
+			}
+		})
+	}
+}
+
+func TestHSLRoundTrip(t *testing.T) {
+	for _, c := range []color.RGBA{
+		{255, 0, 0, 255}, {0, 128, 255, 255}, {206, 174, 51, 255}, {123, 74, 155, 255}, {90, 90, 90, 255},
+	} {
+		h, s, l := toHSL(c)
+		got := fromHSL(h, s, l)
+		for _, d := range []int{int(got.R) - int(c.R), int(got.G) - int(c.G), int(got.B) - int(c.B)} {
+			if d < -1 || d > 1 {
+				t.Errorf("fromHSL(toHSL(%v)) = %v", c, got)
+				break
+			}
+		}
+	}
+}
+
+func TestRenderPalette(t *testing.T) {
+	stats := &Stats{Username: "testuser", Commits: 150, PullRequests: 42, Issues: 18, Reviews: 67, Stars: 23}
+	crimsonArt := splitArt(color.RGBA{15, 20, 45, 255}, color.RGBA{150, 20, 40, 255}, 0.5)
+	grayArt := splitArt(color.RGBA{30, 30, 30, 255}, color.RGBA{120, 120, 120, 255}, 0.5)
+	// Washed-out pink is vivid enough to pick, but its Power Level tint
+	// can't stand out from the bright art behind it
+	paleArt := solidArt(color.RGBA{255, 190, 210, 255})
+
+	tiers := []config.TierConfig{{Name: "Rare", Min: 0, Accent: "#5076A3", Glow: "#8DB8EE"}}
+
+	tests := []struct {
+		name     string
+		art      image.Image
+		tiers    []config.TierConfig
+		wantGold bool
+	}{
+		{"colorful art", crimsonArt, nil, false},
+		{"gray art keeps gold", grayArt, nil, true},
+		{"low contrast keeps gold", paleArt, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := render(tt.art, stats, Options{Palette: true, Tiers: tt.tiers})
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			got := img.RGBAAt(Width/2, 1)
+			if isGold := got == AccentColor; isGold != tt.wantGold {
+				t.Errorf("accent pixel = %v, gold = %v, want gold %v", got, isGold, tt.wantGold)
+			}
+		})
+	}
+
+	t.Run("tiers win", func(t *testing.T) {
+		img, err := render(crimsonArt, stats, Options{Palette: true, Tiers: tiers})
+		if err != nil {
+			t.Fatalf("render() error = %v", err)
+		}
+		want, _ := parseHexColor("#5076A3")
+		if got := img.RGBAAt(Width/2, 1); got != want {
+			t.Errorf("accent pixel = %v, want tier accent %v", got, want)
+		}
+	})
+
+	t.Run("off matches classic", func(t *testing.T) {
+		classic, _ := render(crimsonArt, stats, Options{})
+		plain, _ := render(grayArt, stats, Options{Palette: true})
+		grayClassic, _ := render(grayArt, stats, Options{})
+		if classic.RGBAAt(Width/2, 1) != AccentColor {
+			t.Errorf("classic accent = %v, want gold", classic.RGBAAt(Width/2, 1))
+		}
+		for i := range plain.Pix {
+			if plain.Pix[i] != grayClassic.Pix[i] {
+				t.Fatalf("gold fallback differs from classic render at byte %d", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"sort"
+)
+
+// maxQuantizeSamples bounds the pixels medianCut sorts
+const maxQuantizeSamples = 1 << 17
+
+// medianCut builds a palette of up to n colors for the images by
+// repeatedly splitting the color box with the widest channel range at its
+// median. Results are deterministic.
+func medianCut(imgs []*image.RGBA, n int) color.Palette {
+	total := 0
+	for _, img := range imgs {
+		total += len(img.Pix) / 4
+	}
+	step := total/maxQuantizeSamples + 1
+
+	var samples [][4]uint8
+	for _, img := range imgs {
+		for i := 0; i+3 < len(img.Pix); i += 4 * step {
+			samples = append(samples, [4]uint8{img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]})
+		}
+	}
+	if len(samples) == 0 {
+		return color.Palette{color.RGBA{0, 0, 0, 255}}
+	}
+
+	boxes := [][][4]uint8{samples}
+	for len(boxes) < n {
+		best, bestChannel, bestRange := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if ch, r := widestChannel(box); r > bestRange {
+				best, bestChannel, bestRange = i, ch, r
+			}
+		}
+		if best < 0 {
+			break // every box is a single color
+		}
+
+		box := boxes[best]
+		sort.Slice(box, func(i, j int) bool { return box[i][bestChannel] < box[j][bestChannel] })
+		mid := len(box) / 2
+		boxes[best] = box[:mid]
+		boxes = append(boxes, box[mid:])
+	}
+
+	pal := make(color.Palette, len(boxes))
+	for i, box := range boxes {
+		var sum [4]int
+		for _, s := range box {
+			for ch := range sum {
+				sum[ch] += int(s[ch])
+			}
+		}
+		avg := func(ch int) uint8 { return uint8((sum[ch] + len(box)/2) / len(box)) }
+		pal[i] = color.RGBA{avg(0), avg(1), avg(2), avg(3)}
+	}
+	return pal
+}
+
+// widestChannel returns the channel with the largest value range in box
+func widestChannel(box [][4]uint8) (channel, spread int) {
+	lo := [4]uint8{255, 255, 255, 255}
+	var hi [4]uint8
+	for _, s := range box {
+		for ch := range s {
+			lo[ch] = min(lo[ch], s[ch])
+			hi[ch] = max(hi[ch], s[ch])
+		}
+	}
+	for ch := range lo {
+		if r := int(hi[ch]) - int(lo[ch]); r > spread {
+			channel, spread = ch, r
+		}
+	}
+	return channel, spread
+}
+
+// quantizer maps colors to their nearest palette entry, remembering
+// answers since badge frames repeat most colors
+type quantizer struct {
+	palette color.Palette
+	cache   map[uint32]uint8
+}
+
+func newQuantizer(pal color.Palette) *quantizer {
+	return &quantizer{palette: pal, cache: make(map[uint32]uint8)}
+}
+
+// paletted quantizes the r region of img without dithering, so unchanged
+// pixels map identically in every frame
+func (q *quantizer) paletted(img *image.RGBA, r image.Rectangle) *image.Paletted {
+	p := image.NewPaletted(r, q.palette)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			key := uint32(c.R)<<24 | uint32(c.G)<<16 | uint32(c.B)<<8 | uint32(c.A)
+			idx, ok := q.cache[key]
+			if !ok {
+				idx = uint8(q.palette.Index(c))
+				q.cache[key] = idx
+			}
+			p.Pix[p.PixOffset(x, y)] = idx
+		}
+	}
+	return p
+}
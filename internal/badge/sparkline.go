@@ -0,0 +1,113 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"golang.org/x/image/vector"
+)
+
+// Sparkline placement in the gradient area right of the username
+const (
+	sparklineX      = 360
+	sparklineY      = accentHeight + marginTop + 6
+	sparklineWidth  = 200
+	sparklineHeight = 30
+	sparklineStroke = 1.75 // line width in pixels
+	sparklineDotR   = 2.5  // radius of the latest-value marker
+)
+
+// drawSparkline renders values (oldest first) as an anti-aliased line inside
+// rect with a faint fill beneath and a dot on the latest value. Fewer than two
+// values draws nothing. A flat series is drawn at mid-height.
+func drawSparkline(dst draw.Image, rect image.Rectangle, values []float64, col color.RGBA) {
+	if len(values) < 2 || rect.Empty() {
+		return
+	}
+
+	points := sparklinePoints(values, rect.Dx(), rect.Dy())
+
+	// Faint area fill from the line down to the bottom edge
+	fill := vector.NewRasterizer(rect.Dx(), rect.Dy())
+	fill.MoveTo(points[0][0], float32(rect.Dy()))
+	for _, p := range points {
+		fill.LineTo(p[0], p[1])
+	}
+	fill.LineTo(points[len(points)-1][0], float32(rect.Dy()))
+	fill.ClosePath()
+	fill.Draw(dst, rect, image.NewUniform(color.NRGBA{col.R, col.G, col.B, 40}), image.Point{})
+
+	// Stroke each segment as a thin quad, with round joints to hide seams
+	line := vector.NewRasterizer(rect.Dx(), rect.Dy())
+	for i := 1; i < len(points); i++ {
+		strokeSegment(line, points[i-1], points[i], sparklineStroke/2)
+	}
+	for _, p := range points {
+		circle(line, p[0], p[1], sparklineStroke/2)
+	}
+	last := points[len(points)-1]
+	circle(line, last[0], last[1], sparklineDotR)
+	line.Draw(dst, rect, image.NewUniform(col), image.Point{})
+}
+
+// sparklinePoints maps values onto a w×h box, inset so the stroke and
+// marker stay inside it. Y grows downward, so larger values sit higher.
+func sparklinePoints(values []float64, w, h int) [][2]float32 {
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+
+	inset := float64(sparklineDotR) + 0.5
+	usableW := float64(w) - 2*inset
+	usableH := float64(h) - 2*inset
+
+	points := make([][2]float32, len(values))
+	for i, v := range values {
+		x := inset + usableW*float64(i)/float64(len(values)-1)
+		y := inset + usableH/2
+		if hi > lo {
+			y = inset + usableH*(1-(v-lo)/(hi-lo))
+		}
+		points[i] = [2]float32{float32(x), float32(y)}
+	}
+	return points
+}
+
+// strokeSegment adds a quad of half-width hw around the segment a→b
+func strokeSegment(z *vector.Rasterizer, a, b [2]float32, hw float32) {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	// Unit normal scaled to half-width
+	nx, ny := -dy/length*hw, dx/length*hw
+
+	z.MoveTo(a[0]+nx, a[1]+ny)
+	z.LineTo(b[0]+nx, b[1]+ny)
+	z.LineTo(b[0]-nx, b[1]-ny)
+	z.LineTo(a[0]-nx, a[1]-ny)
+	z.ClosePath()
+}
+
+// circle adds a 16-gon approximating a circle of radius r at (cx, cy).
+// It winds the same way as strokeSegment's quads (counterclockwise on
+// screen) so overlapping coverage adds up instead of cancelling out.
+func circle(z *vector.Rasterizer, cx, cy, r float32) {
+	const sides = 16
+	for i := 0; i <= sides; i++ {
+		angle := -2 * math.Pi * float64(i) / sides
+		x := cx + r*float32(math.Cos(angle))
+		y := cy + r*float32(math.Sin(angle))
+		if i == 0 {
+			z.MoveTo(x, y)
+		} else {
+			z.LineTo(x, y)
+		}
+	}
+	z.ClosePath()
+}
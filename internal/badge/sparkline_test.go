@@ -0,0 +1,111 @@
+package badge
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// sparklineFixture is a fixed series exercising peaks, dips and a flat run
+var sparklineFixture = []float64{3, 5, 2, 8, 13, 9, 4, 4, 10, 15, 12, 7}
+
+func TestSparklineGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+	}{
+		{"sparkline", sparklineFixture},
+		{"sparkline_flat", []float64{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, sparklineWidth, sparklineHeight))
+			drawSparkline(img, img.Bounds(), tt.values, AccentColor)
+
+			goldenPath := filepath.Join("testdata", "golden", tt.name+".png")
+			if *update {
+				writeGolden(t, goldenPath, img)
+				return
+			}
+			compareGolden(t, goldenPath, img)
+		})
+	}
+}
+
+func TestSparklineTooFewValues(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 50, 20))
+	drawSparkline(img, img.Bounds(), []float64{42}, AccentColor)
+
+	for _, px := range img.Pix {
+		if px != 0 {
+			t.Fatal("expected a single value to draw nothing")
+		}
+	}
+}
+
+func TestSparklinePoints(t *testing.T) {
+	points := sparklinePoints([]float64{0, 10}, 100, 20)
+
+	// Higher values sit higher (smaller y); endpoints stay inside the box
+	if points[1][1] >= points[0][1] {
+		t.Errorf("expected rising series to move up, got %v", points)
+	}
+	for _, p := range points {
+		if p[0] < 0 || p[0] > 100 || p[1] < 0 || p[1] > 20 {
+			t.Errorf("point %v outside 100x20 box", p)
+		}
+	}
+}
+
+func writeGolden(t *testing.T, path string, img image.Image) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create golden dir: %v", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create golden file: %v", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatalf("failed to encode golden file: %v", err)
+	}
+}
+
+// compareGolden allows a small per-channel tolerance, since the vector
+// rasterizer's accumulation differs slightly between assembly and pure Go
+func compareGolden(t *testing.T, path string, got *image.RGBA) {
+	t.Helper()
+	want, err := loadImage(path)
+	if err != nil {
+		t.Fatalf("failed to load golden file (run with -update to create): %v", err)
+	}
+	if want.Bounds() != got.Bounds() {
+		t.Fatalf("golden bounds %v, got %v", want.Bounds(), got.Bounds())
+	}
+
+	const tolerance = 2
+	diff := func(a, b uint8) int {
+		if a > b {
+			return int(a - b)
+		}
+		return int(b - a)
+	}
+	for y := got.Bounds().Min.Y; y < got.Bounds().Max.Y; y++ {
+		for x := got.Bounds().Min.X; x < got.Bounds().Max.X; x++ {
+			w := color.RGBAModel.Convert(want.At(x, y)).(color.RGBA)
+			g := got.RGBAAt(x, y)
+			if diff(w.R, g.R) > tolerance || diff(w.G, g.G) > tolerance ||
+				diff(w.B, g.B) > tolerance || diff(w.A, g.A) > tolerance {
+				t.Fatalf("pixel (%d,%d) = %v, golden %v", x, y, g, w)
+			}
+		}
+	}
+}
@@ -4,187 +4,228 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
+	"math"
 
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 )
 
+// textLayers describes the effects composited beneath a text fill. Each
+// is derived from a single rasterized glyph mask: the stroke is the mask
+// dilated, the shadow and glow are blurred copies of the stroke.
+type textLayers struct {
+	Stroke     float64     // outline radius in px (0 = none)
+	Shadow     image.Point // drop shadow offset of the outlined text
+	ShadowBlur float64     // shadow Gaussian sigma in px
+	Glow       float64     // glow Gaussian sigma in px (0 = none)
+	GlowColor  color.Color
+}
+
+const (
+	glowSpread  = 1.5  // glow extends this far past the stroke before blurring
+	glowOpacity = 0.55 // peak glow strength, kept subtle
+)
+
+var (
+	// Full outline for stats and the Power Level: 2px stroke, soft shadow
+	outlineLayers = textLayers{Stroke: 2, Shadow: image.Pt(2, 2), ShadowBlur: 1}
+	// Lighter outline that feels integrated with the art
+	subtleLayers = textLayers{Stroke: 1, Shadow: image.Pt(1, 2), ShadowBlur: 0.8}
+)
+
 // DrawTextWithOutline renders multi-layer text (shadow → stroke → fill)
 // Implements IMAGE-04 requirement for contrast on variable backgrounds
 func DrawTextWithOutline(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color) {
-	point := fixed.Point26_6{
-		X: fixed.I(x),
-		Y: fixed.I(y),
+	drawText(dst, text, x, y, face, fillColor, 0, outlineLayers)
+}
+
+// DrawTextWithTracking renders text with custom letter-spacing (tracking)
+// Uses the same outline rendering as DrawTextWithOutline
+func DrawTextWithTracking(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color, tracking int) {
+	drawText(dst, text, x, y, face, fillColor, tracking, outlineLayers)
+}
+
+// DrawTextSubtle renders text with lighter outline (1px instead of 2px)
+// Use for username and secondary text that should feel integrated, not floating
+func DrawTextSubtle(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color) {
+	drawText(dst, text, x, y, face, fillColor, 0, subtleLayers)
+}
+
+// DrawTextWithGlow renders text with a blurred outer glow beneath the standard outline
+// Use for power level numbers to create Destiny's luminous appearance
+func DrawTextWithGlow(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color, glowColor color.Color) {
+	layers := outlineLayers
+	layers.Glow = 3
+	layers.GlowColor = glowColor
+	drawText(dst, text, x, y, face, fillColor, 0, layers)
+}
+
+// drawText rasterizes text once and composites glow, shadow, stroke and
+// fill from that mask, with the baseline starting at (x, y)
+func drawText(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color, tracking int, layers textLayers) {
+	pad := int(math.Ceil(layers.Stroke+glowSpread)) + blurRadius(math.Max(layers.ShadowBlur, layers.Glow)) + 2
+	glyphs := glyphMask(face, text, tracking, pad)
+	if glyphs == nil {
+		return
 	}
+	origin := image.Pt(x, y)
 
-	// Layer 1: Shadow (offset +2px x/y, black with alpha 0.8)
-	shadowOffsets := []struct{ dx, dy int }{
-		{2, 2}, {3, 3},
+	stroke := glyphs
+	if layers.Stroke > 0 {
+		stroke = dilateMask(glyphs, layers.Stroke)
 	}
-	for _, offset := range shadowOffsets {
-		shadowPoint := fixed.Point26_6{
-			X: point.X + fixed.I(offset.dx),
-			Y: point.Y + fixed.I(offset.dy),
-		}
-		drawer := &font.Drawer{
-			Dst:  dst,
-			Src:  image.NewUniform(ShadowColor),
-			Face: face,
-			Dot:  shadowPoint,
-		}
-		drawer.DrawString(text)
+
+	if layers.Glow > 0 && layers.GlowColor != nil {
+		glow := gaussianBlur(dilateMask(stroke, glowSpread), layers.Glow)
+		compositeMask(dst, glow, origin, fadeColor(layers.GlowColor, glowOpacity))
+	}
+	if layers.Shadow != (image.Point{}) {
+		compositeMask(dst, gaussianBlur(stroke, layers.ShadowBlur), origin.Add(layers.Shadow), ShadowColor)
 	}
+	if layers.Stroke > 0 {
+		compositeMask(dst, stroke, origin, BlackColor)
+	}
+	compositeMask(dst, glyphs, origin, fillColor)
+}
 
-	// Layer 2: Stroke (multi-offset technique, 4px effective width)
-	strokeOffsets := []struct{ dx, dy int }{
-		{-2, -2}, {-2, -1}, {-2, 0}, {-2, 1}, {-2, 2},
-		{-1, -2}, {-1, 2},
-		{0, -2}, {0, 2},
-		{1, -2}, {1, 2},
-		{2, -2}, {2, -1}, {2, 0}, {2, 1}, {2, 2},
+// glyphMask rasterizes text into an alpha mask whose coordinates are
+// relative to the baseline origin, padded by pad px for strokes and blur.
+// Tracking draws rune by rune like measureTextWithTracking measures.
+// Returns nil for text with no visible glyphs.
+func glyphMask(face font.Face, text string, tracking, pad int) *image.Alpha {
+	type run struct {
+		text string
+		x    int
 	}
-	for _, offset := range strokeOffsets {
-		strokePoint := fixed.Point26_6{
-			X: point.X + fixed.I(offset.dx),
-			Y: point.Y + fixed.I(offset.dy),
+	runs := []run{{text, 0}}
+	if tracking != 0 {
+		runs = runs[:0]
+		x := 0
+		for _, char := range text {
+			runs = append(runs, run{string(char), x})
+			x += measureText(face, string(char)) + tracking
 		}
-		drawer := &font.Drawer{
-			Dst:  dst,
-			Src:  image.NewUniform(BlackColor),
-			Face: face,
-			Dot:  strokePoint,
-		}
-		drawer.DrawString(text)
 	}
 
-	// Layer 3: Fill (main text color)
-	drawer := &font.Drawer{
-		Dst:  dst,
-		Src:  image.NewUniform(fillColor),
-		Face: face,
-		Dot:  point,
+	var bounds image.Rectangle
+	for _, r := range runs {
+		b, _ := font.BoundString(face, r.text)
+		rect := image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), b.Max.X.Ceil(), b.Max.Y.Ceil()).Add(image.Pt(r.x, 0))
+		bounds = bounds.Union(rect)
+	}
+	if bounds.Empty() {
+		return nil
 	}
-	drawer.DrawString(text)
-}
 
-// DrawTextWithTracking renders text with custom letter-spacing (tracking)
-// Uses the same outline rendering as DrawTextWithOutline, but draws characters individually
-func DrawTextWithTracking(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color, tracking int) {
-	currentX := x
-	for _, char := range text {
-		charStr := string(char)
-		point := fixed.Point26_6{
-			X: fixed.I(currentX),
-			Y: fixed.I(y),
-		}
+	mask := image.NewAlpha(bounds.Inset(-pad))
+	for _, r := range runs {
+		d := &font.Drawer{Dst: mask, Src: image.Opaque, Face: face, Dot: fixed.P(r.x, 0)}
+		d.DrawString(r.text)
+	}
+	return mask
+}
 
-		// Layer 1: Shadow
-		shadowOffsets := []struct{ dx, dy int }{
-			{2, 2}, {3, 3},
-		}
-		for _, offset := range shadowOffsets {
-			shadowPoint := fixed.Point26_6{
-				X: point.X + fixed.I(offset.dx),
-				Y: point.Y + fixed.I(offset.dy),
-			}
-			drawer := &font.Drawer{
-				Dst:  dst,
-				Src:  image.NewUniform(ShadowColor),
-				Face: face,
-				Dot:  shadowPoint,
+// dilateMask grows a mask by radius px with an anti-aliased round brush
+func dilateMask(src *image.Alpha, radius float64) *image.Alpha {
+	type tap struct {
+		dx, dy int
+		weight float64
+	}
+	var taps []tap
+	reach := int(math.Ceil(radius))
+	for dy := -reach; dy <= reach; dy++ {
+		for dx := -reach; dx <= reach; dx++ {
+			// Full weight within the radius, fading over the next pixel
+			w := math.Min(1, radius+1-math.Hypot(float64(dx), float64(dy)))
+			if w > 0 {
+				taps = append(taps, tap{dx, dy, w})
 			}
-			drawer.DrawString(charStr)
 		}
+	}
 
-		// Layer 2: Stroke
-		strokeOffsets := []struct{ dx, dy int }{
-			{-2, -2}, {-2, -1}, {-2, 0}, {-2, 1}, {-2, 2},
-			{-1, -2}, {-1, 2},
-			{0, -2}, {0, 2},
-			{1, -2}, {1, 2},
-			{2, -2}, {2, -1}, {2, 0}, {2, 1}, {2, 2},
-		}
-		for _, offset := range strokeOffsets {
-			strokePoint := fixed.Point26_6{
-				X: point.X + fixed.I(offset.dx),
-				Y: point.Y + fixed.I(offset.dy),
+	b := src.Bounds()
+	dst := image.NewAlpha(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			best := 0.0
+			for _, t := range taps {
+				sx, sy := x+t.dx, y+t.dy
+				if sx < b.Min.X || sx >= b.Max.X || sy < b.Min.Y || sy >= b.Max.Y {
+					continue
+				}
+				if v := float64(src.Pix[src.PixOffset(sx, sy)]) * t.weight; v > best {
+					best = v
+				}
 			}
-			drawer := &font.Drawer{
-				Dst:  dst,
-				Src:  image.NewUniform(BlackColor),
-				Face: face,
-				Dot:  strokePoint,
-			}
-			drawer.DrawString(charStr)
+			dst.Pix[dst.PixOffset(x, y)] = uint8(math.Round(best))
 		}
-
-		// Layer 3: Fill
-		drawer := &font.Drawer{
-			Dst:  dst,
-			Src:  image.NewUniform(fillColor),
-			Face: face,
-			Dot:  point,
-		}
-		drawer.DrawString(charStr)
-
-		// Advance X by character width + tracking
-		advance := drawer.MeasureString(charStr)
-		currentX += advance.Round() + tracking
 	}
+	return dst
 }
 
-// DrawTextSubtle renders text with lighter outline (1px instead of 2px)
-// Use for username and secondary text that should feel integrated, not floating
-func DrawTextSubtle(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color) {
-	point := fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)}
+// gaussianBlur blurs a mask with a separable Gaussian of the given sigma
+func gaussianBlur(src *image.Alpha, sigma float64) *image.Alpha {
+	if sigma <= 0 {
+		return src
+	}
 
-	// Layer 1: Shadow (lighter than full outline version)
-	shadowPoint := fixed.Point26_6{X: point.X + fixed.I(1), Y: point.Y + fixed.I(2)}
-	drawer := &font.Drawer{Dst: dst, Src: image.NewUniform(ShadowColor), Face: face, Dot: shadowPoint}
-	drawer.DrawString(text)
+	radius := blurRadius(sigma)
+	kernel := make([]float64, 2*radius+1)
+	var sum float64
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
 
-	// Layer 2: Thin stroke (1px offsets instead of 2px)
-	thinOffsets := []struct{ dx, dy int }{
-		{-1, -1}, {-1, 0}, {-1, 1},
-		{0, -1}, {0, 1},
-		{1, -1}, {1, 0}, {1, 1},
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	horizontal := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		row := src.Pix[y*src.Stride:]
+		for x := 0; x < w; x++ {
+			var v float64
+			for k, weight := range kernel {
+				if sx := x + k - radius; sx >= 0 && sx < w {
+					v += float64(row[sx]) * weight
+				}
+			}
+			horizontal[y*w+x] = v
+		}
 	}
-	for _, offset := range thinOffsets {
-		strokePoint := fixed.Point26_6{X: point.X + fixed.I(offset.dx), Y: point.Y + fixed.I(offset.dy)}
-		d := &font.Drawer{Dst: dst, Src: image.NewUniform(BlackColor), Face: face, Dot: strokePoint}
-		d.DrawString(text)
+
+	dst := image.NewAlpha(b)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var v float64
+			for k, weight := range kernel {
+				if sy := y + k - radius; sy >= 0 && sy < h {
+					v += horizontal[sy*w+x] * weight
+				}
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(math.Min(255, math.Round(v)))
+		}
 	}
+	return dst
+}
 
-	// Layer 3: Fill
-	d := &font.Drawer{Dst: dst, Src: image.NewUniform(fillColor), Face: face, Dot: point}
-	d.DrawString(text)
+// blurRadius is how far a Gaussian of sigma reaches before it's negligible
+func blurRadius(sigma float64) int {
+	return int(math.Ceil(3 * sigma))
 }
 
-// DrawTextWithGlow renders text with an outer glow effect followed by standard outline
-// Use for power level numbers to create Destiny's luminous appearance
-func DrawTextWithGlow(dst draw.Image, text string, x, y int, face font.Face, fillColor color.Color, glowColor color.Color) {
-	point := fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)}
-
-	// Layer 0: Glow (low-alpha fill color at +-3px offsets)
-	glowRGBA := glowColor.(color.RGBA)
-	glowAlpha := color.RGBA{
-		R: glowRGBA.R,
-		G: glowRGBA.G,
-		B: glowRGBA.B,
-		A: 40, // very subtle
-	}
-	glowOffsets := []struct{ dx, dy int }{
-		{-3, -3}, {-3, 0}, {-3, 3},
-		{0, -3}, {0, 3},
-		{3, -3}, {3, 0}, {3, 3},
-		{-2, -2}, {-2, 2}, {2, -2}, {2, 2},
-	}
-	for _, offset := range glowOffsets {
-		glowPoint := fixed.Point26_6{X: point.X + fixed.I(offset.dx), Y: point.Y + fixed.I(offset.dy)}
-		d := &font.Drawer{Dst: dst, Src: image.NewUniform(glowAlpha), Face: face, Dot: glowPoint}
-		d.DrawString(text)
-	}
-
-	// Then standard shadow + stroke + fill via DrawTextWithOutline
-	DrawTextWithOutline(dst, text, x, y, face, fillColor)
+// compositeMask draws c through mask over dst, with the mask's origin at origin
+func compositeMask(dst draw.Image, mask *image.Alpha, origin image.Point, c color.Color) {
+	r := mask.Bounds().Add(origin)
+	draw.DrawMask(dst, r, image.NewUniform(c), image.Point{}, mask, mask.Bounds().Min, draw.Over)
+}
+
+// fadeColor scales any color's opacity by f
+func fadeColor(c color.Color, f float64) color.Color {
+	r, g, b, a := c.RGBA()
+	scale := func(v uint32) uint16 { return uint16(float64(v) * f) }
+	return color.RGBA64{scale(r), scale(g), scale(b), scale(a)}
 }
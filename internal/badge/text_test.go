@@ -95,3 +95,104 @@ func TestDrawTextWithOutline(t *testing.T) {
 		t.Errorf("expected at least 2 distinct alpha values from multi-layer rendering, got %d", len(alphaValues))
 	}
 }
+
+func TestDrawTextWithGlowAnyColor(t *testing.T) {
+	ttf, err := opentype.Parse(interBoldFontData)
+	if err != nil {
+		t.Fatalf("failed to parse embedded font: %v", err)
+	}
+	face, err := opentype.NewFace(ttf, &opentype.FaceOptions{Size: 24, DPI: 72})
+	if err != nil {
+		t.Fatalf("failed to create font face: %v", err)
+	}
+	defer face.Close()
+
+	tests := []struct {
+		name string
+		glow color.Color
+	}{
+		{"RGBA", color.RGBA{245, 217, 106, 255}},
+		{"NRGBA", color.NRGBA{80, 118, 163, 200}},
+		{"Gray", color.Gray{200}},
+		{"RGBA64", color.RGBA64{0xffff, 0x8000, 0, 0xffff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+			DrawTextWithGlow(img, "2568", 40, 60, face, WhiteColor, tt.glow)
+
+			// The glow reaches beyond the 2px stroke and shadow, fading out
+			var glowAlphas = map[uint8]bool{}
+			for y := 0; y < 100; y++ {
+				if c := img.RGBAAt(35, y); c.A > 0 {
+					glowAlphas[c.A] = true
+				}
+			}
+			if len(glowAlphas) < 3 {
+				t.Errorf("expected a soft glow left of the text, got alphas %v", glowAlphas)
+			}
+			if c := img.RGBAAt(0, 0); c.A != 0 {
+				t.Errorf("glow leaked to the corner: %v", c)
+			}
+		})
+	}
+}
+
+func TestDilateMask(t *testing.T) {
+	src := image.NewAlpha(image.Rect(-5, -5, 6, 6))
+	src.SetAlpha(0, 0, color.Alpha{255})
+
+	got := dilateMask(src, 2)
+	tests := []struct {
+		p    image.Point
+		want uint8
+	}{
+		{image.Pt(0, 0), 255},
+		{image.Pt(2, 0), 255},  // within the radius
+		{image.Pt(0, -2), 255}, // same distance on the other axis
+		{image.Pt(3, 0), 0},    // past the radius
+	}
+	for _, tt := range tests {
+		if a := got.AlphaAt(tt.p.X, tt.p.Y).A; a != tt.want {
+			t.Errorf("dilated alpha at %v = %d, want %d", tt.p, a, tt.want)
+		}
+	}
+
+	// Just past the radius falls on the anti-aliased edge, and corners
+	// are rounded off
+	if a := got.AlphaAt(2, 1).A; a == 0 || a == 255 {
+		t.Errorf("edge alpha at (2,1) = %d, want partial coverage", a)
+	}
+	if a := got.AlphaAt(2, 2).A; a >= 64 {
+		t.Errorf("corner alpha at (2,2) = %d, want mostly clear", a)
+	}
+}
+
+func TestGaussianBlur(t *testing.T) {
+	src := image.NewAlpha(image.Rect(0, 0, 21, 21))
+	for y := 5; y < 16; y++ {
+		for x := 5; x < 16; x++ {
+			src.SetAlpha(x, y, color.Alpha{255})
+		}
+	}
+
+	got := gaussianBlur(src, 1.5)
+	var before, after int
+	for i := range src.Pix {
+		before += int(src.Pix[i])
+		after += int(got.Pix[i])
+	}
+	if diff := after - before; diff < -before/100 || diff > before/100 {
+		t.Errorf("blur changed total coverage from %d to %d", before, after)
+	}
+	if a := got.AlphaAt(10, 10).A; a < 250 {
+		t.Errorf("center alpha = %d, want nearly opaque", a)
+	}
+	if a := got.AlphaAt(4, 10).A; a == 0 || a >= 128 {
+		t.Errorf("alpha just outside the edge = %d, want a soft falloff", a)
+	}
+	if gaussianBlur(src, 0) != src {
+		t.Error("zero sigma should return the mask unchanged")
+	}
+}
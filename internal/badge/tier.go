@@ -0,0 +1,109 @@
+package badge
+
+import (
+	"image/color"
+	"image/draw"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"golang.org/x/image/font"
+)
+
+// tierLabelTracking is the letter-spacing of the tier name, in pixels
+const tierLabelTracking = 2
+
+// tierStyle holds the colors and icon that vary by light-level tier
+type tierStyle struct {
+	Accent     color.RGBA
+	PowerLevel color.RGBA
+	Divider    color.Color // stat bar cell separators
+	Icon       string
+	Label      string // empty hides the tier name
+}
+
+// classicStyle is the untiered gold look
+func classicStyle() tierStyle {
+	return tierStyle{
+		Accent:     AccentColor,
+		PowerLevel: PowerLevelColor,
+		Divider:    DividerColor,
+		Icon:       config.IconDiamond,
+	}
+}
+
+// tierStyleFor resolves a tier's styling, falling back to the classic gold
+// for colors and icon the tier leaves unset
+func tierStyleFor(tier config.TierConfig) tierStyle {
+	style := classicStyle()
+	style.Label = tier.Name
+	if c, ok := parseHexColor(tier.Accent); ok {
+		style.Accent = c
+	}
+	if c, ok := parseHexColor(tier.Glow); ok {
+		style.PowerLevel = c
+	}
+	if tier.Icon != "" {
+		style.Icon = tier.Icon
+	}
+	return style
+}
+
+// drawTierIcon draws the Power Level icon centered at (cx, cy) within a
+// halfW×halfH diamond footprint: shadow, black outline, then the shape fill
+func drawTierIcon(dst draw.Image, icon string, cx, cy, halfW, halfH int, col color.RGBA) {
+	switch icon {
+	case config.IconDiamondOutline:
+		// Hollow diamond: full diamond with a dark core
+		drawOutlinedDiamond(dst, cx, cy, halfW, halfH, col)
+		drawDiamond(dst, cx, cy, halfW-4, halfH-4, BlackColor)
+	case config.IconDiamondInset:
+		// Diamond with a bright inner facet
+		drawOutlinedDiamond(dst, cx, cy, halfW, halfH, col)
+		drawDiamond(dst, cx, cy, halfW/2, halfH/2, blend(col, WhiteColor, 0.6))
+	case config.IconDoubleDiamond:
+		// Two overlapping diamonds sharing the footprint
+		smallW, smallH := halfW*2/3, halfH*2/3
+		offset := halfW - smallW
+		drawOutlinedDiamond(dst, cx-offset, cy, smallW, smallH, col)
+		drawOutlinedDiamond(dst, cx+offset, cy, smallW, smallH, col)
+	case config.IconStar:
+		// Four-point sparkle: tall and wide slim diamonds over a small core
+		drawDiamond(dst, cx+2, cy+2, halfW/3+1, halfH+3, ShadowColor)
+		drawDiamond(dst, cx, cy, halfW/3+2, halfH+4, BlackColor)
+		drawDiamond(dst, cx, cy, halfW+4, halfH/3+2, BlackColor)
+		drawDiamond(dst, cx, cy, halfW/3, halfH+2, col)
+		drawDiamond(dst, cx, cy, halfW+2, halfH/3, col)
+		drawDiamond(dst, cx, cy, halfW/2, halfH/2, col)
+	default:
+		drawOutlinedDiamond(dst, cx, cy, halfW, halfH, col)
+	}
+}
+
+// drawOutlinedDiamond draws a diamond with drop shadow and black outline
+func drawOutlinedDiamond(dst draw.Image, cx, cy, halfW, halfH int, col color.Color) {
+	// Layer 1: shadow
+	drawDiamond(dst, cx+2, cy+2, halfW+1, halfH+1, ShadowColor)
+	// Layer 2: black outline
+	drawDiamond(dst, cx, cy, halfW+2, halfH+2, BlackColor)
+	// Layer 3: fill
+	drawDiamond(dst, cx, cy, halfW, halfH, col)
+}
+
+// blend mixes a toward b by t (0 = a, 1 = b), keeping a's alpha
+func blend(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), a.A}
+}
+
+// measureTextWithTracking returns the width of text drawn by DrawTextWithTracking
+func measureTextWithTracking(face font.Face, text string, tracking int) int {
+	width := 0
+	for _, char := range text {
+		width += measureText(face, string(char)) + tracking
+	}
+	if width > 0 {
+		width -= tracking
+	}
+	return width
+}
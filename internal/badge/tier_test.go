@@ -0,0 +1,75 @@
+package badge
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+func TestTierStyleFor(t *testing.T) {
+	style := tierStyleFor(config.TierConfig{Name: "Rare", Accent: "#5076A3", Icon: config.IconDiamondInset})
+
+	if style.Accent != (color.RGBA{0x50, 0x76, 0xA3, 255}) {
+		t.Errorf("unexpected accent %v", style.Accent)
+	}
+	// Unset glow keeps the classic gold
+	if style.PowerLevel != PowerLevelColor {
+		t.Errorf("expected classic Power Level color, got %v", style.PowerLevel)
+	}
+	if style.Icon != config.IconDiamondInset || style.Label != "Rare" {
+		t.Errorf("unexpected icon/label %q/%q", style.Icon, style.Label)
+	}
+}
+
+func TestDrawTierIcon(t *testing.T) {
+	for _, icon := range config.TierIcons {
+		t.Run(icon, func(t *testing.T) {
+			img := image.NewRGBA(image.Rect(0, 0, 40, 40))
+			fill := color.RGBA{10, 200, 30, 255}
+			drawTierIcon(img, icon, 20, 20, 10, 10, fill)
+
+			found := false
+			for y := 0; y < 40 && !found; y++ {
+				for x := 0; x < 40; x++ {
+					if img.RGBAAt(x, y) == fill {
+						found = true
+						break
+					}
+				}
+			}
+			if !found {
+				t.Errorf("icon %s drew no fill pixels", icon)
+			}
+			if img.RGBAAt(0, 0).A != 0 {
+				t.Errorf("icon %s spilled outside its footprint", icon)
+			}
+		})
+	}
+}
+
+func TestGenerateWithTiers(t *testing.T) {
+	tiers := []config.TierConfig{
+		{Name: "Common", Min: 0, Accent: "#102030"},
+		{Name: "Exotic", Min: 1000, Accent: "#A0B0C0"},
+	}
+	stats := &Stats{Username: "tiered", Commits: 1500}
+	outputPath := filepath.Join(t.TempDir(), "badge.png")
+
+	if err := GenerateWithOptions("testdata/test_emblem.jpg", stats, outputPath, Options{Tiers: tiers}); err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+
+	img, err := loadImage(outputPath)
+	if err != nil {
+		t.Fatalf("failed to load badge: %v", err)
+	}
+
+	// Accent line (inside the border) uses the Exotic tier color
+	r, g, b, _ := img.At(Width/2, 1).RGBA()
+	if got := (color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 255}); got != (color.RGBA{0xA0, 0xB0, 0xC0, 255}) {
+		t.Errorf("accent line color = %v, want #A0B0C0", got)
+	}
+}
@@ -0,0 +1,32 @@
+package badge
+
+import (
+	"image/color"
+	"image/draw"
+)
+
+// Triumph row layout beneath the username
+const (
+	triumphX       = 138 // first icon center, aligned under the username
+	triumphY       = accentHeight + marginTop + 40
+	triumphHalf    = 5  // icon half-size (10px footprint)
+	triumphSpacing = 20 // center-to-center distance
+	maxTriumphs    = 8  // more would run into the sparkline
+)
+
+// Triumph is an earned achievement shown as a small icon
+type Triumph struct {
+	Name string
+	Icon string // tier icon shape, e.g. "star"
+}
+
+// drawTriumphs draws up to maxTriumphs icons left to right
+func drawTriumphs(dst draw.Image, triumphs []Triumph, col color.RGBA) {
+	for i, triumph := range triumphs {
+		if i == maxTriumphs {
+			break
+		}
+		cx := triumphX + i*triumphSpacing
+		drawTierIcon(dst, triumph.Icon, cx, triumphY, triumphHalf, triumphHalf, col)
+	}
+}
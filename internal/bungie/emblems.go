@@ -0,0 +1,299 @@
+package bungie
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ItemTypeEmblem is DestinyItemType.Emblem in the inventory item definitions
+const ItemTypeEmblem = 14
+
+// Emblem is an emblem definition from the cached manifest
+type Emblem struct {
+	Hash        string `json:"hash"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`      // displaySource, e.g. "Source: Complete the campaign"
+	SeasonHash  uint32 `json:"season_hash,omitempty"` // DestinySeasonDefinition hash; 0 when unseasoned
+	// Artwork URLs on bungie.net; empty when the manifest has no such image
+	Icon             string `json:"icon,omitempty"`
+	SecondaryIcon    string `json:"secondary_icon,omitempty"`
+	SecondarySpecial string `json:"secondary_special,omitempty"`
+}
+
+// EmblemFilter narrows a list of emblems; zero values match everything
+type EmblemFilter struct {
+	Query      string // case-insensitive match on name, description or hash
+	Source     string // case-insensitive substring of displaySource
+	SeasonHash uint32
+}
+
+// EmblemIssue is a configured emblem (hash or name) that can't be used
+type EmblemIssue struct {
+	Ref     string
+	Problem string
+}
+
+func (i EmblemIssue) Error() string {
+	return fmt.Sprintf("emblem %s: %s", i.Ref, i.Problem)
+}
+
+// loadManifest parses the cached inventory item definitions
+func loadManifest(manifestPath string) (map[string]emblemData, error) {
+	data, err := os.ReadFile(manifestPath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("manifest not cached at %s (run fetch-emblem first)", manifestPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest map[string]emblemData
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// IsHash reports whether ref is a numeric item hash rather than a name
+func IsHash(ref string) bool {
+	if ref == "" {
+		return false
+	}
+	for _, r := range ref {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveEmblem looks up an emblem by hash or exact (case-insensitive) name
+func ResolveEmblem(manifestPath, ref string) (Emblem, error) {
+	manifest, err := OpenManifest(manifestPath)
+	if err != nil {
+		return Emblem{}, err
+	}
+	return manifest.Resolve(ref)
+}
+
+// Manifest is a parsed manifest, for resolving several emblems without
+// re-reading the file
+type Manifest struct {
+	items map[string]emblemData
+}
+
+// OpenManifest parses the cached inventory item definitions
+func OpenManifest(manifestPath string) (*Manifest, error) {
+	items, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{items: items}, nil
+}
+
+// Resolve looks up an emblem by hash or exact (case-insensitive) name
+func (m *Manifest) Resolve(ref string) (Emblem, error) {
+	hash, item, problem := resolveRef(m.items, ref)
+	if problem != "" {
+		return Emblem{}, EmblemIssue{Ref: ref, Problem: problem}
+	}
+	return emblemFromItem(hash, item), nil
+}
+
+// CheckEmblems verifies each hash or name resolves to a manifest emblem with
+// badge artwork (secondarySpecial or secondaryIcon)
+func CheckEmblems(manifestPath string, refs []string) ([]EmblemIssue, error) {
+	manifest, err := loadManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []EmblemIssue
+	for _, ref := range refs {
+		_, item, problem := resolveRef(manifest, ref)
+		if problem == "" && item.SecondarySpecial == "" && item.SecondaryIcon == "" {
+			problem = fmt.Sprintf("%q has no secondarySpecial or secondaryIcon artwork", item.DisplayProperties.Name)
+		}
+		if problem != "" {
+			issues = append(issues, EmblemIssue{Ref: ref, Problem: problem})
+		}
+	}
+	return issues, nil
+}
+
+// resolveRef finds the emblem a hash or name refers to; problem explains
+// why it can't be used
+func resolveRef(manifest map[string]emblemData, ref string) (hash string, item emblemData, problem string) {
+	if IsHash(ref) {
+		item, ok := manifest[ref]
+		if !ok {
+			return "", item, "not found in manifest"
+		}
+		if item.ItemType != ItemTypeEmblem {
+			return "", item, fmt.Sprintf("%q is not an emblem (itemType %d)", item.DisplayProperties.Name, item.ItemType)
+		}
+		return ref, item, ""
+	}
+
+	var matches, similar []string
+	for h, it := range manifest {
+		if it.ItemType != ItemTypeEmblem {
+			continue
+		}
+		name := it.DisplayProperties.Name
+		if strings.EqualFold(name, ref) {
+			matches = append(matches, h)
+		} else if ref != "" && strings.Contains(strings.ToLower(name), strings.ToLower(ref)) {
+			similar = append(similar, name)
+		}
+	}
+	sort.Strings(matches)
+	sort.Strings(similar)
+
+	switch {
+	case len(matches) == 1:
+		return matches[0], manifest[matches[0]], ""
+	case len(matches) > 1:
+		return "", item, fmt.Sprintf("name matches %d emblems, use a hash: %s", len(matches), strings.Join(matches, ", "))
+	case len(similar) > 0:
+		if len(similar) > 3 {
+			similar = similar[:3]
+		}
+		return "", item, fmt.Sprintf("no emblem with this name (did you mean %s?)", strings.Join(quoteAll(similar), ", "))
+	default:
+		return "", item, "no emblem with this name"
+	}
+}
+
+// emblemFromItem converts a manifest entry into an Emblem
+func emblemFromItem(hash string, item emblemData) Emblem {
+	return Emblem{
+		Hash:             hash,
+		Name:             item.DisplayProperties.Name,
+		Description:      item.DisplayProperties.Description,
+		Source:           item.DisplaySource,
+		SeasonHash:       item.SeasonHash,
+		Icon:             imageURL(item.DisplayProperties.Icon),
+		SecondaryIcon:    imageURL(item.SecondaryIcon),
+		SecondarySpecial: imageURL(item.SecondarySpecial),
+	}
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return quoted
+}
+
+// LoadEmblems reads every emblem (itemType 14) from a cached manifest,
+// sorted by name then hash
+func LoadEmblems(manifestPath string) ([]Emblem, error) {
+	manifest, err := OpenManifest(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Emblems(), nil
+}
+
+// Emblems lists every emblem in the manifest, sorted by name then hash
+func (m *Manifest) Emblems() []Emblem {
+	var emblems []Emblem
+	for hash, item := range m.items {
+		if item.ItemType != ItemTypeEmblem {
+			continue
+		}
+		emblems = append(emblems, emblemFromItem(hash, item))
+	}
+
+	sort.Slice(emblems, func(i, j int) bool {
+		if emblems[i].Name != emblems[j].Name {
+			return emblems[i].Name < emblems[j].Name
+		}
+		return emblems[i].Hash < emblems[j].Hash
+	})
+	return emblems
+}
+
+// FilterEmblems returns the emblems matching every set field of the filter
+func FilterEmblems(emblems []Emblem, f EmblemFilter) []Emblem {
+	query := strings.ToLower(f.Query)
+	source := strings.ToLower(f.Source)
+
+	var matches []Emblem
+	for _, e := range emblems {
+		if query != "" && e.Hash != f.Query &&
+			!strings.Contains(strings.ToLower(e.Name), query) &&
+			!strings.Contains(strings.ToLower(e.Description), query) {
+			continue
+		}
+		if source != "" && !strings.Contains(strings.ToLower(e.Source), source) {
+			continue
+		}
+		if f.SeasonHash != 0 && e.SeasonHash != f.SeasonHash {
+			continue
+		}
+		matches = append(matches, e)
+	}
+	return matches
+}
+
+// ArtURL returns the artwork the badge would use, preferring the high-res
+// secondarySpecial like FetchEmblem does; empty when the emblem has no art
+func (e Emblem) ArtURL() string {
+	switch {
+	case e.SecondarySpecial != "":
+		return e.SecondarySpecial
+	case e.SecondaryIcon != "":
+		return e.SecondaryIcon
+	default:
+		return e.Icon
+	}
+}
+
+// DownloadEmblemArt saves an emblem's artwork to outputPath
+func DownloadEmblemArt(e Emblem, outputPath string) error {
+	return DownloadEmblemArtWith(nil, e, outputPath)
+}
+
+// DownloadEmblemArtWith is DownloadEmblemArt over the given client; nil
+// uses a 60s timeout
+func DownloadEmblemArtWith(client *http.Client, e Emblem, outputPath string) error {
+	url := e.ArtURL()
+	if url == "" {
+		return fmt.Errorf("emblem %s has no artwork", e.Hash)
+	}
+	if err := downloadImage(client, url, outputPath); err != nil {
+		return fmt.Errorf("failed to download emblem %s: %w", e.Hash, err)
+	}
+	return nil
+}
+
+// RotationYAML renders emblems as a ready-to-paste emblems.rotation block
+func RotationYAML(emblems []Emblem) string {
+	var b strings.Builder
+	b.WriteString("emblems:\n  rotation:\n")
+	for _, e := range emblems {
+		fmt.Fprintf(&b, "    - %s", strconv.Quote(e.Hash))
+		if e.Name != "" {
+			fmt.Fprintf(&b, "  # %s", e.Name)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// imageURL turns a manifest image path into an absolute bungie.net URL
+func imageURL(path string) string {
+	if path == "" {
+		return ""
+	}
+	return BungieBaseURL + path
+}
@@ -0,0 +1,243 @@
+package bungie
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testManifest = `{
+  "4052831236": {
+    "displayProperties": {"name": "Activate ESCALATION", "description": "Warmind protocols engaged.", "icon": "/common/destiny2_content/icons/a.jpg"},
+    "secondaryIcon": "/common/destiny2_content/icons/a_wide.jpg",
+    "itemType": 14,
+    "displaySource": "Source: Escalation Protocol",
+    "seasonHash": 2809059425
+  },
+  "1901885391": {
+    "displayProperties": {"name": "A Crimson Cathedral", "description": "Blood on the altar."},
+    "secondarySpecial": "/common/destiny2_content/icons/b_special.jpg",
+    "itemType": 14,
+    "displaySource": "Source: Crimson Days"
+  },
+  "2962058744": {
+    "displayProperties": {"name": "Placeholder", "icon": "/common/destiny2_content/icons/c.jpg"},
+    "itemType": 14
+  },
+  "347366834": {
+    "displayProperties": {"name": "Ace of Spades", "description": "A hand cannon."},
+    "itemType": 3
+  }
+}`
+
+func writeTestManifest(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, []byte(testManifest), 0644); err != nil {
+		t.Fatalf("Failed to write test manifest: %v", err)
+	}
+	return path
+}
+
+func TestLoadEmblems(t *testing.T) {
+	emblems, err := LoadEmblems(writeTestManifest(t))
+	if err != nil {
+		t.Fatalf("LoadEmblems failed: %v", err)
+	}
+
+	if len(emblems) != 3 {
+		t.Fatalf("got %d emblems, want 3 (weapons skipped)", len(emblems))
+	}
+	// Sorted by name
+	if emblems[0].Hash != "1901885391" || emblems[1].Hash != "4052831236" || emblems[2].Hash != "2962058744" {
+		t.Errorf("unexpected order: %s, %s, %s", emblems[0].Hash, emblems[1].Hash, emblems[2].Hash)
+	}
+
+	e := emblems[1]
+	if e.Icon != BungieBaseURL+"/common/destiny2_content/icons/a.jpg" {
+		t.Errorf("Icon = %q, want absolute URL", e.Icon)
+	}
+	if e.SecondarySpecial != "" {
+		t.Errorf("SecondarySpecial = %q, want empty", e.SecondarySpecial)
+	}
+	if e.SeasonHash != 2809059425 {
+		t.Errorf("SeasonHash = %d, want 2809059425", e.SeasonHash)
+	}
+}
+
+func TestLoadEmblemsMissingManifest(t *testing.T) {
+	_, err := LoadEmblems(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil || !strings.Contains(err.Error(), "not cached") {
+		t.Errorf("expected not cached error, got %v", err)
+	}
+}
+
+func TestFilterEmblems(t *testing.T) {
+	emblems, err := LoadEmblems(writeTestManifest(t))
+	if err != nil {
+		t.Fatalf("LoadEmblems failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter EmblemFilter
+		want   []string
+	}{
+		{"no filter", EmblemFilter{}, []string{"1901885391", "4052831236", "2962058744"}},
+		{"name", EmblemFilter{Query: "crimson"}, []string{"1901885391"}},
+		{"description", EmblemFilter{Query: "WARMIND"}, []string{"4052831236"}},
+		{"hash", EmblemFilter{Query: "4052831236"}, []string{"4052831236"}},
+		{"source", EmblemFilter{Source: "escalation"}, []string{"4052831236"}},
+		{"season", EmblemFilter{SeasonHash: 2809059425}, []string{"4052831236"}},
+		{"combined mismatch", EmblemFilter{Query: "crimson", Source: "escalation"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FilterEmblems(emblems, tt.filter)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d emblems, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Hash != tt.want[i] {
+					t.Errorf("got[%d] = %s, want %s", i, got[i].Hash, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRotationYAML(t *testing.T) {
+	got := RotationYAML([]Emblem{
+		{Hash: "4052831236", Name: "Activate ESCALATION"},
+		{Hash: "1901885391"},
+	})
+	want := "emblems:\n  rotation:\n    - \"4052831236\"  # Activate ESCALATION\n    - \"1901885391\"\n"
+	if got != want {
+		t.Errorf("RotationYAML() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEmblemArtURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		emblem Emblem
+		want   string
+	}{
+		{"special preferred", Emblem{SecondarySpecial: "s", SecondaryIcon: "w", Icon: "i"}, "s"},
+		{"wide banner", Emblem{SecondaryIcon: "w", Icon: "i"}, "w"},
+		{"icon only", Emblem{Icon: "i"}, "i"},
+		{"no art", Emblem{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.emblem.ArtURL(); got != tt.want {
+				t.Errorf("ArtURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckEmblems(t *testing.T) {
+	path := writeTestManifest(t)
+
+	tests := []struct {
+		name   string
+		hashes []string
+		want   []string // hashes with issues
+	}{
+		{"all valid", []string{"4052831236", "1901885391"}, nil},
+		{"typo", []string{"405283123"}, []string{"405283123"}},
+		{"not an emblem", []string{"347366834"}, []string{"347366834"}},
+		{"no banner art", []string{"2962058744"}, []string{"2962058744"}},
+		{"mixed", []string{"4052831236", "347366834", "1"}, []string{"347366834", "1"}},
+		{"by name", []string{"a crimson cathedral", "Activate ESCALATION"}, nil},
+		{"misspelled name", []string{"A Crimson Cathedrall"}, []string{"A Crimson Cathedrall"}},
+		{"weapon name", []string{"Ace of Spades"}, []string{"Ace of Spades"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := CheckEmblems(path, tt.hashes)
+			if err != nil {
+				t.Fatalf("CheckEmblems failed: %v", err)
+			}
+			if len(issues) != len(tt.want) {
+				t.Fatalf("got %v, want issues for %v", issues, tt.want)
+			}
+			for i, issue := range issues {
+				if issue.Ref != tt.want[i] {
+					t.Errorf("issue %d for %s, want %s", i, issue.Ref, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestResolveEmblem(t *testing.T) {
+	path := writeTestManifest(t)
+
+	tests := []struct {
+		name     string
+		ref      string
+		wantHash string
+		wantErr  string
+	}{
+		{"hash", "4052831236", "4052831236", ""},
+		{"exact name", "A Crimson Cathedral", "1901885391", ""},
+		{"case-insensitive name", "activate escalation", "4052831236", ""},
+		{"unknown hash", "405283123", "", "not found"},
+		{"not an emblem", "347366834", "", "not an emblem"},
+		{"partial name suggests", "Crimson", "", `did you mean "A Crimson Cathedral"`},
+		{"unknown name", "Nonexistent", "", "no emblem with this name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := ResolveEmblem(path, tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ResolveEmblem(%q) error = %v, want %q", tt.ref, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveEmblem(%q) failed: %v", tt.ref, err)
+			}
+			if e.Hash != tt.wantHash {
+				t.Errorf("ResolveEmblem(%q) = %s, want %s", tt.ref, e.Hash, tt.wantHash)
+			}
+		})
+	}
+}
+
+func TestResolveEmblemAmbiguousName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	manifest := `{
+  "1": {"displayProperties": {"name": "Twin"}, "itemType": 14},
+  "2": {"displayProperties": {"name": "Twin"}, "itemType": 14}
+}`
+	if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write test manifest: %v", err)
+	}
+
+	_, err := ResolveEmblem(path, "Twin")
+	if err == nil || !strings.Contains(err.Error(), "use a hash: 1, 2") {
+		t.Errorf("expected ambiguity error listing hashes, got %v", err)
+	}
+}
+
+func TestIsHash(t *testing.T) {
+	for ref, want := range map[string]bool{
+		"4052831236":          true,
+		"A Crimson Cathedral": false,
+		"40528x":              false,
+		"":                    false,
+	} {
+		if got := IsHash(ref); got != want {
+			t.Errorf("IsHash(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
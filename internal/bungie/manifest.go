@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"time"
 )
 
@@ -13,10 +14,11 @@ const (
 	BungieBaseURL = "https://www.bungie.net"
 	ManifestAPI   = BungieBaseURL + "/Platform/Destiny2/Manifest/"
 	UserAgent     = "ContribEmblem/1.0 (+https://github.com/castrojo/contribemblem)"
-	ManifestCache = "data/manifest.json"
-	EmblemOutput  = "data/emblem.jpg"
 )
 
+// MaxArtSize caps downloaded emblem artwork, in bytes
+const MaxArtSize = 20 << 20
+
 // Manifest API response structures
 type manifestResponse struct {
 	ErrorCode   int    `json:"ErrorCode"`
@@ -33,16 +35,22 @@ type manifestResponse struct {
 // Emblem data from manifest
 type emblemData struct {
 	DisplayProperties struct {
-		Icon string `json:"icon"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Icon        string `json:"icon"`
 	} `json:"displayProperties"`
 	SecondaryIcon    string `json:"secondaryIcon"`    // 474x96 wide banner
 	SecondarySpecial string `json:"secondarySpecial"` // high-res detail view (1920x1080+)
+	ItemType         int    `json:"itemType"`
+	DisplaySource    string `json:"displaySource"`
+	SeasonHash       uint32 `json:"seasonHash"`
 }
 
 // FetchEmblem downloads emblem artwork from Bungie API
+// manifestPath: cached item definitions, refreshed when older than a day
 // emblemHash: emblem identifier (e.g., "1409726931")
-// Returns path to downloaded emblem image
-func FetchEmblem(emblemHash string) error {
+// outputPath: where the emblem image is saved
+func FetchEmblem(manifestPath, emblemHash, outputPath string) error {
 	apiKey := os.Getenv("BUNGIE_API_KEY")
 	if apiKey == "" {
 		return fmt.Errorf("BUNGIE_API_KEY environment variable not set")
@@ -50,23 +58,13 @@ func FetchEmblem(emblemHash string) error {
 
 	fmt.Fprintf(os.Stderr, "Fetching emblem hash: %s\n", emblemHash)
 
-	// Fetch manifest metadata
-	fmt.Fprintf(os.Stderr, "Fetching Bungie manifest metadata...\n")
-	manifestURL, err := getManifestURL(apiKey)
-	if err != nil {
-		return fmt.Errorf("failed to get manifest URL: %w", err)
-	}
-
-	fmt.Fprintf(os.Stderr, "Manifest URL: %s\n", manifestURL)
-
-	// Download manifest if not cached
-	if err := downloadManifestIfNeeded(manifestURL); err != nil {
-		return fmt.Errorf("failed to download manifest: %w", err)
+	if err := ensureManifest(apiKey, manifestPath); err != nil {
+		return err
 	}
 
 	// Look up emblem in manifest
 	fmt.Fprintf(os.Stderr, "Looking up emblem %s in manifest...\n", emblemHash)
-	iconPath, err := lookupEmblemIcon(emblemHash)
+	iconPath, err := lookupEmblemIcon(manifestPath, emblemHash)
 	if err != nil {
 		return fmt.Errorf("failed to lookup emblem: %w", err)
 	}
@@ -74,11 +72,41 @@ func FetchEmblem(emblemHash string) error {
 	// Download emblem image
 	iconURL := BungieBaseURL + iconPath
 	fmt.Fprintf(os.Stderr, "Downloading emblem image from: %s\n", iconURL)
-	if err := downloadImage(iconURL, EmblemOutput); err != nil {
+	if err := downloadImage(nil, iconURL, outputPath); err != nil {
 		return fmt.Errorf("failed to download emblem image: %w", err)
 	}
 
-	fmt.Fprintf(os.Stderr, "✓ Emblem image saved to %s\n", EmblemOutput)
+	fmt.Fprintf(os.Stderr, "✓ Emblem image saved to %s\n", outputPath)
+	return nil
+}
+
+// EnsureManifest downloads the item definitions to manifestPath unless a
+// fresh copy is cached. Without BUNGIE_API_KEY an existing cache is used as is.
+func EnsureManifest(manifestPath string) error {
+	apiKey := os.Getenv("BUNGIE_API_KEY")
+	if apiKey == "" {
+		if _, err := os.Stat(manifestPath); err == nil {
+			return nil
+		}
+		return fmt.Errorf("manifest not cached at %s and BUNGIE_API_KEY not set", manifestPath)
+	}
+	return ensureManifest(apiKey, manifestPath)
+}
+
+func ensureManifest(apiKey, manifestPath string) error {
+	// Fetch manifest metadata
+	fmt.Fprintf(os.Stderr, "Fetching Bungie manifest metadata...\n")
+	manifestURL, err := getManifestURL(apiKey)
+	if err != nil {
+		return fmt.Errorf("failed to get manifest URL: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Manifest URL: %s\n", manifestURL)
+
+	// Download manifest if not cached
+	if err := downloadManifestIfNeeded(manifestURL, manifestPath); err != nil {
+		return fmt.Errorf("failed to download manifest: %w", err)
+	}
 	return nil
 }
 
@@ -125,9 +153,9 @@ func getManifestURL(apiKey string) (string, error) {
 	return BungieBaseURL + manifestURL, nil
 }
 
-func downloadManifestIfNeeded(url string) error {
+func downloadManifestIfNeeded(url, manifestPath string) error {
 	// Check if manifest exists and is fresh (< 24 hours old)
-	if info, err := os.Stat(ManifestCache); err == nil {
+	if info, err := os.Stat(manifestPath); err == nil {
 		age := time.Since(info.ModTime())
 		if age < 24*time.Hour {
 			fmt.Fprintf(os.Stderr, "✓ Using cached manifest (age: %v)\n", age.Round(time.Minute))
@@ -156,11 +184,11 @@ func downloadManifestIfNeeded(url string) error {
 	}
 
 	// Ensure data directory exists
-	if err := os.MkdirAll("data", 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(manifestPath), 0755); err != nil {
 		return err
 	}
 
-	out, err := os.Create(ManifestCache)
+	out, err := os.Create(manifestPath)
 	if err != nil {
 		return err
 	}
@@ -175,8 +203,8 @@ func downloadManifestIfNeeded(url string) error {
 	return nil
 }
 
-func lookupEmblemIcon(emblemHash string) (string, error) {
-	data, err := os.ReadFile(ManifestCache)
+func lookupEmblemIcon(manifestPath, emblemHash string) (string, error) {
+	data, err := os.ReadFile(manifestPath)
 	if err != nil {
 		return "", err
 	}
@@ -209,14 +237,18 @@ func lookupEmblemIcon(emblemHash string) (string, error) {
 	return emblem.DisplayProperties.Icon, nil
 }
 
-func downloadImage(url, outputPath string) error {
+// downloadImage saves the image at url to outputPath, writing it only
+// once the whole image has arrived. A nil client uses a 60s timeout.
+func downloadImage(client *http.Client, url, outputPath string) error {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("User-Agent", UserAgent)
 
-	client := &http.Client{Timeout: 60 * time.Second}
+	if client == nil {
+		client = &http.Client{Timeout: 60 * time.Second}
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
@@ -227,18 +259,19 @@ func downloadImage(url, outputPath string) error {
 		return fmt.Errorf("HTTP %d", resp.StatusCode)
 	}
 
-	// Ensure data directory exists
-	if err := os.MkdirAll("data", 0755); err != nil {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxArtSize+1))
+	if err != nil {
 		return err
 	}
+	if len(data) > MaxArtSize {
+		return fmt.Errorf("image exceeds %d MB", MaxArtSize>>20)
+	}
 
-	// Save raw download directly to avoid JPEG re-encoding artifacts
-	out, err := os.Create(outputPath)
-	if err != nil {
+	// Ensure output directory exists
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return err
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	return err
+	// Save raw download directly to avoid JPEG re-encoding artifacts
+	return os.WriteFile(outputPath, data, 0644)
 }
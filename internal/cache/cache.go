@@ -0,0 +1,121 @@
+// Package cache stores fetched GitHub stats with metadata recording who
+// they belong to and when they were fetched, so commands can tell whether
+// data/stats.json is fresh enough to reuse
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+// Meta describes cached stats. It is written next to them as
+// <name>.meta.json so stats.json keeps its format.
+type Meta struct {
+	Username  string    `json:"username"`
+	Year      int       `json:"year"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Entry is cached stats and their metadata
+type Entry struct {
+	Stats *github.Stats
+	Meta  Meta
+}
+
+// MetaPath returns the metadata path for a stats file,
+// e.g. data/stats.meta.json for data/stats.json
+func MetaPath(statsPath string) string {
+	return strings.TrimSuffix(statsPath, filepath.Ext(statsPath)) + ".meta.json"
+}
+
+// Save writes stats and their metadata
+func Save(path, username string, stats *github.Stats, now time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	meta := Meta{Username: username, Year: stats.Year, FetchedAt: now.UTC()}
+	data, err = json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache metadata: %w", err)
+	}
+	if err := os.WriteFile(MetaPath(path), data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", MetaPath(path), err)
+	}
+	return nil
+}
+
+// Load reads cached stats. Stats without metadata (written before it
+// existed, or by redirecting fetch-stats) get it from their year and
+// updated_at, with an unknown username.
+func Load(path string) (*Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var stats github.Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	entry := &Entry{Stats: &stats}
+	data, err = os.ReadFile(MetaPath(path))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &entry.Meta); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", MetaPath(path), err)
+		}
+	case os.IsNotExist(err):
+		entry.Meta.Year = stats.Year
+		entry.Meta.FetchedAt, _ = time.Parse(time.RFC3339, stats.UpdatedAt)
+	default:
+		return nil, fmt.Errorf("reading %s: %w", MetaPath(path), err)
+	}
+	return entry, nil
+}
+
+// Problems lists why the entry shouldn't be used for username at now:
+// older than ttl (or of unknown age), fetched for another user, or for
+// another year. An unknown username is not held against it.
+func (e *Entry) Problems(username string, ttl time.Duration, now time.Time) []string {
+	var problems []string
+	if e.Meta.FetchedAt.IsZero() {
+		problems = append(problems, "fetch time unknown")
+	} else if age := now.Sub(e.Meta.FetchedAt); age >= ttl {
+		problems = append(problems, "fetched "+FormatAge(age)+" ago")
+	}
+	if e.Meta.Username != "" && username != "" && !strings.EqualFold(e.Meta.Username, username) {
+		problems = append(problems, fmt.Sprintf("fetched for %s, not %s", e.Meta.Username, username))
+	}
+	if year := now.UTC().Year(); e.Meta.Year != 0 && e.Meta.Year != year {
+		problems = append(problems, fmt.Sprintf("stats are for %d, not %d", e.Meta.Year, year))
+	}
+	return problems
+}
+
+// Fresh reports whether the entry can be reused for username at now
+func (e *Entry) Fresh(username string, ttl time.Duration, now time.Time) bool {
+	return len(e.Problems(username, ttl, now)) == 0
+}
+
+// FormatAge renders an age for humans: minutes under two days, then days
+func FormatAge(age time.Duration) string {
+	if age >= 48*time.Hour {
+		return fmt.Sprintf("%d days", int(age.Hours()/24))
+	}
+	return age.Round(time.Minute).String()
+}
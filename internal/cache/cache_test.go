@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "stats.json")
+	now := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	stats := &github.Stats{Year: 2026, UpdatedAt: "2026-10-18T09:30:00Z", Commits: 42}
+
+	if err := Save(path, "castrojo", stats, now); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), "stats.meta.json")); err != nil {
+		t.Errorf("metadata not written next to stats: %v", err)
+	}
+
+	entry, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if entry.Stats.Commits != 42 {
+		t.Errorf("Commits = %d, want 42", entry.Stats.Commits)
+	}
+	want := Meta{Username: "castrojo", Year: 2026, FetchedAt: now}
+	if !entry.Meta.FetchedAt.Equal(want.FetchedAt) || entry.Meta.Username != want.Username || entry.Meta.Year != want.Year {
+		t.Errorf("Meta = %+v, want %+v", entry.Meta, want)
+	}
+}
+
+func TestLoadWithoutMeta(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	if err := os.WriteFile(path, []byte(`{"year": 2026, "updated_at": "2026-10-18T09:30:00Z", "commits": 7}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if entry.Meta.Username != "" || entry.Meta.Year != 2026 {
+		t.Errorf("Meta = %+v, want year from stats and unknown user", entry.Meta)
+	}
+	if !entry.Meta.FetchedAt.Equal(time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("FetchedAt = %v, want updated_at", entry.Meta.FetchedAt)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "stats.json")); err == nil {
+		t.Error("expected an error for missing stats")
+	}
+}
+
+func TestProblems(t *testing.T) {
+	fetched := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	const ttl = 6 * time.Hour
+
+	tests := []struct {
+		name     string
+		meta     Meta
+		username string
+		ttl      time.Duration
+		now      time.Time
+		want     []string // substrings, one per problem
+	}{
+		{"fresh", Meta{"castrojo", 2026, fetched}, "castrojo", ttl, fetched.Add(time.Hour), nil},
+		{"case-insensitive user", Meta{"CastroJo", 2026, fetched}, "castrojo", ttl, fetched.Add(time.Hour), nil},
+		{"unknown user", Meta{"", 2026, fetched}, "castrojo", ttl, fetched.Add(time.Hour), nil},
+		{"stale", Meta{"castrojo", 2026, fetched}, "castrojo", ttl, fetched.Add(7 * time.Hour), []string{"7h0m0s ago"}},
+		{"zero ttl", Meta{"castrojo", 2026, fetched}, "castrojo", 0, fetched, []string{"ago"}},
+		{"other user", Meta{"jeefy", 2026, fetched}, "castrojo", ttl, fetched.Add(time.Hour), []string{"for jeefy"}},
+		{"unknown age", Meta{Username: "castrojo", Year: 2026}, "castrojo", ttl, fetched, []string{"unknown"}},
+		{"last year", Meta{"castrojo", 2025, fetched.AddDate(0, 0, -1)}, "castrojo", ttl, fetched.AddDate(0, 3, 0), []string{"ago", "for 2025"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &Entry{Stats: &github.Stats{}, Meta: tt.meta}
+			got := entry.Problems(tt.username, tt.ttl, tt.now)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Problems() = %q, want %d problem(s)", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("problem %d = %q, want it to mention %q", i, got[i], want)
+				}
+			}
+			if entry.Fresh(tt.username, tt.ttl, tt.now) != (len(tt.want) == 0) {
+				t.Error("Fresh() disagrees with Problems()")
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := map[time.Duration]string{
+		90 * time.Second:     "2m0s",
+		7 * time.Hour:        "7h0m0s",
+		47 * time.Hour:       "47h0m0s",
+		100 * 24 * time.Hour: "100 days",
+	}
+	for age, want := range tests {
+		if got := FormatAge(age); got != want {
+			t.Errorf("FormatAge(%v) = %q, want %q", age, got, want)
+		}
+	}
+}
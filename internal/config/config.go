@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -11,6 +14,43 @@ const (
 	DefaultConfigPath = "contribemblem.yml"
 )
 
+// Metric keys, matching the YAML keys of the metrics section
+const (
+	MetricCommits         = "commits"
+	MetricPullRequests    = "pull_requests"
+	MetricIssues          = "issues"
+	MetricReviews         = "reviews"
+	MetricStars           = "stars"
+	MetricCurrentStreak   = "current_streak"
+	MetricLongestStreak   = "longest_streak"
+	MetricActiveDaysRatio = "active_days_ratio"
+	MetricMergedPRs       = "merged_pull_requests"
+	MetricReposContrib    = "repositories_contributed"
+	MetricFollowers       = "followers"
+	MetricDiscussions     = "discussion_answers"
+	MetricGists           = "gists"
+)
+
+// AllMetrics lists every known metric key in display order
+var AllMetrics = []string{
+	MetricCommits,
+	MetricPullRequests,
+	MetricIssues,
+	MetricReviews,
+	MetricStars,
+	MetricCurrentStreak,
+	MetricLongestStreak,
+	MetricActiveDaysRatio,
+	MetricMergedPRs,
+	MetricReposContrib,
+	MetricFollowers,
+	MetricDiscussions,
+	MetricGists,
+}
+
+// MaxMetrics is the most stat bar cells that fit legibly on the badge
+const MaxMetrics = 8
+
 // Config represents the YAML configuration structure
 type Config struct {
 	// Username to fetch GitHub stats for
@@ -21,6 +61,253 @@ type Config struct {
 
 	// Emblem rotation list
 	Emblems EmblemsConfig `yaml:"emblems"`
+
+	// Optional badge decorations
+	Badge BadgeConfig `yaml:"badge"`
+
+	// Power Level formula
+	PowerLevel PowerLevelConfig `yaml:"power_level"`
+
+	// Achievement (triumph) milestones
+	Achievements AchievementsConfig `yaml:"achievements"`
+
+	// Badge file format and size
+	Output OutputConfig `yaml:"output"`
+
+	// HTTP badge server (contribemblem serve)
+	Serve ServeConfig `yaml:"serve"`
+
+	// Reuse of fetched stats between commands
+	Cache CacheConfig `yaml:"cache"`
+
+	// README the badge is injected into
+	Readme ReadmeConfig `yaml:"readme"`
+}
+
+// DefaultReadmePath is used when readme.path is unset
+const DefaultReadmePath = "README.md"
+
+// ReadmeConfig locates the README that update-readme and run edit
+type ReadmeConfig struct {
+	// Path to the README, relative to the working directory (default README.md)
+	Path string `yaml:"path"`
+}
+
+// ReadmePath returns the configured README path or the default
+func (r *ReadmeConfig) ReadmePath() string {
+	if r.Path == "" {
+		return DefaultReadmePath
+	}
+	return r.Path
+}
+
+// DefaultStatsTTL is used when cache.ttl is unset
+const DefaultStatsTTL = 6 * time.Hour
+
+// CacheConfig controls how long the cached stats.json is reused
+type CacheConfig struct {
+	// TTL is how old cached stats may be before run and fetch-stats refetch
+	// them, e.g. "12h" (default 6h; "0" always refetches)
+	TTL string `yaml:"ttl"`
+}
+
+// StatsTTL returns the configured stats TTL or the default
+func (c *CacheConfig) StatsTTL() time.Duration {
+	if c.TTL == "" {
+		return DefaultStatsTTL
+	}
+	d, err := time.ParseDuration(c.TTL)
+	if err != nil || d < 0 {
+		return DefaultStatsTTL
+	}
+	return d
+}
+
+func (c *CacheConfig) validate() error {
+	if c.TTL == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(c.TTL); err != nil || d < 0 {
+		return fmt.Errorf("cache.ttl %q is not a duration like \"6h\"", c.TTL)
+	}
+	return nil
+}
+
+// Badge server defaults
+const (
+	DefaultServeAddr = ":8080"
+	DefaultServeTTL  = time.Hour
+	// AnyUser in serve.users lets the server render badges for anyone
+	AnyUser = "*"
+)
+
+// ServeConfig controls the HTTP badge server
+type ServeConfig struct {
+	// Addr to listen on (default ":8080")
+	Addr string `yaml:"addr"`
+	// TTL is how long fetched stats and emblems are reused, e.g. "30m" (default 1h)
+	TTL string `yaml:"ttl"`
+	// Users whose badges may be served; "*" allows anyone (default: username only)
+	Users []string `yaml:"users"`
+}
+
+// Address returns the configured listen address or the default
+func (s *ServeConfig) Address() string {
+	if s.Addr == "" {
+		return DefaultServeAddr
+	}
+	return s.Addr
+}
+
+// CacheTTL returns the configured cache TTL or the default
+func (s *ServeConfig) CacheTTL() time.Duration {
+	d, err := time.ParseDuration(s.TTL)
+	if err != nil || d <= 0 {
+		return DefaultServeTTL
+	}
+	return d
+}
+
+func (s *ServeConfig) validate() error {
+	if s.TTL != "" {
+		d, err := time.ParseDuration(s.TTL)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("serve.ttl %q is not a positive duration like \"1h\"", s.TTL)
+		}
+	}
+	for i, user := range s.Users {
+		if user != AnyUser && !IsUsername(user) {
+			return fmt.Errorf("serve.users[%d] %q is not a GitHub username", i, user)
+		}
+	}
+	return nil
+}
+
+// IsUsername reports whether name is a valid GitHub username: up to 39
+// letters, digits or single hyphens, not starting or ending with a hyphen
+func IsUsername(name string) bool {
+	if name == "" || len(name) > 39 || name[0] == '-' || name[len(name)-1] == '-' || strings.Contains(name, "--") {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// Output defaults
+const (
+	DefaultBadgePath = "badge.png" // used when output.badge is unset
+	DefaultDataDir   = "data"      // used when output.data_dir is unset
+)
+
+// PNG compression levels
+const (
+	CompressionDefault = "default"
+	CompressionBest    = "best"
+	CompressionFast    = "fast"
+	CompressionNone    = "none"
+)
+
+// CompressionLevels lists every known PNG compression level
+var CompressionLevels = []string{CompressionDefault, CompressionBest, CompressionFast, CompressionNone}
+
+// Badge output extensions; the format is chosen by extension
+var OutputExtensions = []string{".png", ".jpg", ".jpeg"}
+
+// OutputConfig controls how the badge file is written
+type OutputConfig struct {
+	// Badge is the output path; .png (default badge.png) or .jpg/.jpeg
+	Badge string `yaml:"badge"`
+	// Compression is the PNG compression level (default "default")
+	Compression string `yaml:"compression"`
+	// Colors quantizes PNG output to a palette of 2-256 colors (0 = off)
+	Colors int `yaml:"colors"`
+	// Quality is the JPEG quality, 1-100 (default 90)
+	Quality int `yaml:"quality"`
+	// DataDir holds fetched stats, emblems, the manifest and history
+	// (default data)
+	DataDir string `yaml:"data_dir"`
+}
+
+// BadgePath returns the configured badge path or the default
+func (o *OutputConfig) BadgePath() string {
+	if o.Badge == "" {
+		return DefaultBadgePath
+	}
+	return o.Badge
+}
+
+// DataDirectory returns the configured data directory or the default
+func (o *OutputConfig) DataDirectory() string {
+	if o.DataDir == "" {
+		return DefaultDataDir
+	}
+	return o.DataDir
+}
+
+func (o *OutputConfig) validate() error {
+	ext := strings.ToLower(filepath.Ext(o.BadgePath()))
+	if !contains(OutputExtensions, ext) {
+		return fmt.Errorf("output.badge %q must end in one of %v", o.Badge, OutputExtensions)
+	}
+	if o.Compression != "" && !contains(CompressionLevels, o.Compression) {
+		return fmt.Errorf("output.compression %q is not one of %v", o.Compression, CompressionLevels)
+	}
+	if o.Colors != 0 && (o.Colors < 2 || o.Colors > 256) {
+		return fmt.Errorf("output.colors must be between 2 and 256")
+	}
+	if o.Colors != 0 && ext != ".png" {
+		return fmt.Errorf("output.colors only applies to PNG output")
+	}
+	if o.Quality != 0 && (o.Quality < 1 || o.Quality > 100) {
+		return fmt.Errorf("output.quality must be between 1 and 100")
+	}
+	return nil
+}
+
+// DefaultMaxTriumphIcons is used when achievements.max_icons is unset
+const DefaultMaxTriumphIcons = 5
+
+// AchievementsConfig defines milestone triumphs shown on the badge
+type AchievementsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// MaxIcons limits how many triumph icons the badge shows (default 5)
+	MaxIcons int `yaml:"max_icons"`
+	// Rules replace the built-in milestones when set
+	Rules []AchievementRule `yaml:"rules"`
+}
+
+// DefaultAchievementRules returns the built-in milestones, used when
+// achievements.rules is unset
+func DefaultAchievementRules() []AchievementRule {
+	return []AchievementRule{
+		{ID: "commits-1k", Name: "1K Commits", Metric: MetricCommits, Threshold: 1000, Icon: IconDiamond},
+		{ID: "reviews-100", Name: "100 Reviews", Metric: MetricReviews, Threshold: 100, Icon: IconDiamondInset},
+		{ID: "merged-100", Name: "100 Merged PRs", Metric: MetricMergedPRs, Threshold: 100, Icon: IconDoubleDiamond},
+		{ID: "streak-30", Name: "30-Day Streak", Metric: MetricLongestStreak, Threshold: 30, Icon: IconDiamondOutline},
+		{ID: "stars-1k", Name: "First 1K Stars", Metric: MetricStars, Threshold: 1000, Icon: IconStar},
+	}
+}
+
+// RuleList returns the configured rules or the defaults
+func (a *AchievementsConfig) RuleList() []AchievementRule {
+	if len(a.Rules) == 0 {
+		return DefaultAchievementRules()
+	}
+	return a.Rules
+}
+
+// AchievementRule unlocks once Metric reaches Threshold
+type AchievementRule struct {
+	ID        string  `yaml:"id"`
+	Name      string  `yaml:"name"`
+	Metric    string  `yaml:"metric"`
+	Threshold float64 `yaml:"threshold"`
+	// Icon is a tier icon shape (see TierIcons); defaults to star
+	Icon string `yaml:"icon"`
 }
 
 // MetricsConfig defines which metrics to display
@@ -30,14 +317,315 @@ type MetricsConfig struct {
 	Issues       bool `yaml:"issues"`
 	Reviews      bool `yaml:"reviews"`
 	Stars        bool `yaml:"stars"`
+
+	// Consistency metrics derived from the contribution calendar
+	CurrentStreak   bool `yaml:"current_streak"`
+	LongestStreak   bool `yaml:"longest_streak"`
+	ActiveDaysRatio bool `yaml:"active_days_ratio"`
+
+	// Additional profile metrics
+	MergedPullRequests      bool `yaml:"merged_pull_requests"`
+	RepositoriesContributed bool `yaml:"repositories_contributed"`
+	Followers               bool `yaml:"followers"`
+	DiscussionAnswers       bool `yaml:"discussion_answers"`
+	Gists                   bool `yaml:"gists"`
+}
+
+// Enabled returns the keys of the enabled metrics in display order
+func (m MetricsConfig) Enabled() []string {
+	toggles := []struct {
+		key string
+		on  bool
+	}{
+		{MetricCommits, m.Commits},
+		{MetricPullRequests, m.PullRequests},
+		{MetricIssues, m.Issues},
+		{MetricReviews, m.Reviews},
+		{MetricStars, m.Stars},
+		{MetricCurrentStreak, m.CurrentStreak},
+		{MetricLongestStreak, m.LongestStreak},
+		{MetricActiveDaysRatio, m.ActiveDaysRatio},
+		{MetricMergedPRs, m.MergedPullRequests},
+		{MetricReposContrib, m.RepositoriesContributed},
+		{MetricFollowers, m.Followers},
+		{MetricDiscussions, m.DiscussionAnswers},
+		{MetricGists, m.Gists},
+	}
+
+	var keys []string
+	for _, t := range toggles {
+		if t.on {
+			keys = append(keys, t.key)
+		}
+	}
+	return keys
 }
 
 // EmblemsConfig defines emblem rotation settings
 type EmblemsConfig struct {
 	Rotation []string `yaml:"rotation"`
 	Fallback string   `yaml:"fallback"`
+
+	// Strategy picks the weekly emblem from the rotation (default weekly-hash)
+	Strategy string `yaml:"strategy"`
+	// Weights per emblem for the weighted strategy (default 1)
+	Weights map[string]float64 `yaml:"weights"`
+	// Pinned emblems override the strategy during their date ranges
+	Pinned []PinnedEmblem `yaml:"pinned"`
+	// Unlocks gate rotation emblems behind a Power Level or achievement
+	Unlocks map[string]EmblemUnlock `yaml:"unlocks"`
+	// CropOffsets override badge.crop for individual emblems
+	CropOffsets map[string]CropOffset `yaml:"crop_offsets"`
+}
+
+// Emblem selection strategies
+const (
+	StrategyWeeklyHash = "weekly-hash" // SHA256 of the ISO week, same for everyone
+	StrategyRoundRobin = "round-robin" // next emblem every week
+	StrategyShuffle    = "shuffle"     // shuffled cycles, no repeats within a cycle
+	StrategyUsername   = "username"    // weekly hash seeded by username
+	StrategyWeighted   = "weighted"    // weighted random pick seeded by week and username
+)
+
+// Strategies lists every known selection strategy
+var Strategies = []string{StrategyWeeklyHash, StrategyRoundRobin, StrategyShuffle, StrategyUsername, StrategyWeighted}
+
+// PinnedEmblem forces an emblem between two yearly dates (inclusive).
+// Ranges may wrap the new year, e.g. from "12-15" to "01-06".
+type PinnedEmblem struct {
+	Emblem string `yaml:"emblem"`
+	From   string `yaml:"from"` // MM-DD
+	To     string `yaml:"to"`   // MM-DD
+}
+
+// EmblemUnlock is what a rotation emblem requires before it can be selected
+type EmblemUnlock struct {
+	MinPowerLevel int `yaml:"min_power_level"`
+	// Achievement is a triumph ID that must be in the achievements ledger
+	Achievement string `yaml:"achievement"`
+}
+
+// BadgeConfig defines optional badge decorations
+type BadgeConfig struct {
+	// Tiers styles the badge by Power Level tier (see power_level.tiers)
+	Tiers bool `yaml:"tiers"`
+	// Deltas shows week-over-week changes beneath stat values (from data/history)
+	Deltas bool `yaml:"deltas"`
+	// LanguageBar draws a segmented top-languages bar along the stat bar
+	LanguageBar bool `yaml:"language_bar"`
+	// TopLanguages limits the bar to the N largest languages (default 5)
+	TopLanguages int `yaml:"top_languages"`
+	// Sparkline draws recent weekly activity right of the username
+	Sparkline SparklineConfig `yaml:"sparkline"`
+	// Crop picks which part of the emblem art fills the badge (default center)
+	Crop string `yaml:"crop"`
+	// AdaptiveContrast fits overlay darkness to the emblem art
+	AdaptiveContrast bool `yaml:"adaptive_contrast"`
+	// ContrastTarget is the WCAG ratio adaptive contrast aims for (default 4.5)
+	ContrastTarget float64 `yaml:"contrast_target"`
+	// Palette colors the accent from the emblem art instead of gold
+	Palette bool `yaml:"palette"`
+	// Animation additionally renders an animated badge
+	Animation AnimationConfig `yaml:"animation"`
+}
+
+// Emblem art crop strategies
+const (
+	CropCenter  = "center"  // middle of the art
+	CropLeft    = "left"    // left edge, like Destiny's in-game banner
+	CropEntropy = "entropy" // most detailed region of the art
+)
+
+// CropStrategies lists every known crop strategy
+var CropStrategies = []string{CropCenter, CropLeft, CropEntropy}
+
+// CropOffset positions the crop window within the art: 0 is the left/top
+// edge, 1 the right/bottom edge. Unset axes are centered.
+type CropOffset struct {
+	X *float64 `yaml:"x"`
+	Y *float64 `yaml:"y"`
+}
+
+// Sparkline data sources
+const (
+	SparklineCalendar = "calendar" // weekly contributions from the contribution calendar
+	SparklineHistory  = "history"  // weekly Power Level from data/history snapshots
+)
+
+// Sparkline week bounds
+const (
+	MinSparklineWeeks     = 12
+	MaxSparklineWeeks     = 52
+	DefaultSparklineWeeks = 26
+)
+
+// SparklineConfig defines the optional activity sparkline
+type SparklineConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Weeks of activity to show, 12-52 (default 26)
+	Weeks int `yaml:"weeks"`
+	// Source is "calendar" (default) or "history"
+	Source string `yaml:"source"`
 }
 
+// Animated badge formats
+const (
+	FormatGIF  = "gif"
+	FormatAPNG = "apng"
+)
+
+// Animation effects
+const (
+	EffectCountUp  = "count-up" // Power Level counts up from zero
+	EffectShimmer  = "shimmer"  // exotic shimmer sweeps along the accent line
+	EffectRotation = "rotation" // cross-fade through the rotation's emblems
+)
+
+// AnimationEffects lists every known animation effect
+var AnimationEffects = []string{EffectCountUp, EffectShimmer, EffectRotation}
+
+// Animation frame bounds and defaults
+const (
+	MinAnimationFrames     = 2
+	MaxAnimationFrames     = 120
+	DefaultAnimationFrames = 24
+	DefaultAnimationLoop   = 2 * time.Second
+	// Browsers slow GIF frames shorter than 20ms down to 100ms
+	MinAnimationFrameDelay = 20 * time.Millisecond
+	// APNG stores frame delays as 16-bit milliseconds
+	MaxAnimationFrameDelay = 65535 * time.Millisecond
+)
+
+// AnimationConfig defines the optional animated badge, written alongside
+// the static PNG
+type AnimationConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Format is "gif" (default) or "apng"
+	Format string `yaml:"format"`
+	// Effects to combine (default count-up and shimmer)
+	Effects []string `yaml:"effects"`
+	// Frames per loop, 2-120 (default 24)
+	Frames int `yaml:"frames"`
+	// Duration of one loop, e.g. "2s" (default 2s)
+	Duration string `yaml:"duration"`
+	// Output path (default badge.gif, or badge.apng for APNG)
+	Output string `yaml:"output"`
+}
+
+// FrameCount returns the configured frames or the default
+func (a *AnimationConfig) FrameCount() int {
+	if a.Frames == 0 {
+		return DefaultAnimationFrames
+	}
+	return a.Frames
+}
+
+// LoopDuration returns the configured loop length or the default
+func (a *AnimationConfig) LoopDuration() time.Duration {
+	d, err := time.ParseDuration(a.Duration)
+	if err != nil || d <= 0 {
+		return DefaultAnimationLoop
+	}
+	return d
+}
+
+// EffectList returns the configured effects or the defaults
+func (a *AnimationConfig) EffectList() []string {
+	if len(a.Effects) == 0 {
+		return []string{EffectCountUp, EffectShimmer}
+	}
+	return a.Effects
+}
+
+// OutputPath returns where the animated badge is written
+func (a *AnimationConfig) OutputPath() string {
+	switch {
+	case a.Output != "":
+		return a.Output
+	case a.Format == FormatAPNG:
+		return "badge.apng"
+	default:
+		return "badge.gif"
+	}
+}
+
+func (a *AnimationConfig) validate() error {
+	if a.Format != "" && a.Format != FormatGIF && a.Format != FormatAPNG {
+		return fmt.Errorf("badge.animation.format must be %q or %q", FormatGIF, FormatAPNG)
+	}
+	for _, effect := range a.Effects {
+		if !contains(AnimationEffects, effect) {
+			return fmt.Errorf("badge.animation.effects: %q is not one of %v", effect, AnimationEffects)
+		}
+	}
+	if f := a.Frames; f != 0 && (f < MinAnimationFrames || f > MaxAnimationFrames) {
+		return fmt.Errorf("badge.animation.frames must be between %d and %d", MinAnimationFrames, MaxAnimationFrames)
+	}
+	if a.Duration != "" {
+		d, err := time.ParseDuration(a.Duration)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("badge.animation.duration %q is not a positive duration like \"2s\"", a.Duration)
+		}
+	}
+	delay := a.LoopDuration() / time.Duration(a.FrameCount())
+	if delay < MinAnimationFrameDelay {
+		return fmt.Errorf("badge.animation: %d frames in %v is under %v per frame", a.FrameCount(), a.LoopDuration(), MinAnimationFrameDelay)
+	}
+	if delay > MaxAnimationFrameDelay {
+		return fmt.Errorf("badge.animation: %d frames in %v is over %v per frame", a.FrameCount(), a.LoopDuration(), MaxAnimationFrameDelay)
+	}
+	return nil
+}
+
+// PowerLevelConfig tunes how metrics combine into the Power Level.
+// The zero value reproduces the classic raw sum of the five built-in metrics.
+type PowerLevelConfig struct {
+	// Weights per metric key, merged over the defaults (1 for the five
+	// built-ins, 0 for everything else). Set a weight to 0 to drop a metric.
+	Weights map[string]float64 `yaml:"weights"`
+	// LogScale replaces each raw value v with 100*log10(1+v) before weighting,
+	// so large counts (e.g. stars) no longer swamp small ones
+	LogScale bool `yaml:"log_scale"`
+	// Caps limit each metric's raw value before scaling and weighting
+	Caps map[string]float64 `yaml:"caps"`
+	// SoftCap is the level above which gains are slowed by SoftCapRate
+	SoftCap int `yaml:"soft_cap"`
+	// SoftCapRate is the fraction of gains kept above SoftCap (default 0.5)
+	SoftCapRate float64 `yaml:"soft_cap_rate"`
+	// PinnacleCap is the hard maximum Power Level (0 means uncapped)
+	PinnacleCap int `yaml:"pinnacle_cap"`
+	// Tiers override the default Common→Exotic light-level tiers
+	Tiers []TierConfig `yaml:"tiers"`
+}
+
+// Tier icon shapes drawn next to the Power Level
+const (
+	IconDiamondOutline = "diamond-outline"
+	IconDiamond        = "diamond"
+	IconDiamondInset   = "diamond-inset"
+	IconDoubleDiamond  = "double-diamond"
+	IconStar           = "star"
+)
+
+// TierIcons lists every known tier icon shape
+var TierIcons = []string{IconDiamondOutline, IconDiamond, IconDiamondInset, IconDoubleDiamond, IconStar}
+
+// TierConfig is a Power Level threshold with its badge styling
+type TierConfig struct {
+	Name string `yaml:"name"`
+	// Min is the lowest Power Level in this tier
+	Min int `yaml:"min"`
+	// Accent colors the top line and divider glow ("#RRGGBB")
+	Accent string `yaml:"accent"`
+	// Glow colors the Power Level number and icon ("#RRGGBB")
+	Glow string `yaml:"glow"`
+	// Icon is the shape drawn left of the Power Level
+	Icon string `yaml:"icon"`
+}
+
+// DefaultTopLanguages is used when badge.top_languages is unset
+const DefaultTopLanguages = 5
+
 // Load reads and parses the YAML configuration file
 func Load(path string) (*Config, error) {
 	// Check if file exists
@@ -71,10 +659,13 @@ func (c *Config) Validate() error {
 	}
 
 	// At least one metric must be enabled
-	if !c.Metrics.Commits && !c.Metrics.PullRequests && !c.Metrics.Issues &&
-		!c.Metrics.Reviews && !c.Metrics.Stars {
+	enabled := len(c.Metrics.Enabled())
+	if enabled == 0 {
 		return fmt.Errorf("at least one metric must be enabled")
 	}
+	if enabled > MaxMetrics {
+		return fmt.Errorf("at most %d metrics can be enabled, got %d", MaxMetrics, enabled)
+	}
 
 	// Emblem rotation must have at least one emblem
 	if len(c.Emblems.Rotation) == 0 {
@@ -86,6 +677,9 @@ func (c *Config) Validate() error {
 		if emblem == "" {
 			return fmt.Errorf("emblems.rotation[%d] is empty", i)
 		}
+		if emblem == "file:" {
+			return fmt.Errorf("emblems.rotation[%d] file: path is empty", i)
+		}
 	}
 
 	// Fallback emblem should be specified
@@ -93,9 +687,252 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("emblems.fallback is required")
 	}
 
+	if err := c.Emblems.validateSelection(); err != nil {
+		return err
+	}
+	for emblem, unlock := range c.Emblems.Unlocks {
+		if unlock.Achievement != "" && !c.Achievements.Enabled {
+			return fmt.Errorf("emblems.unlocks.%s requires achievements.enabled", emblem)
+		}
+		if unlock.Achievement != "" && !c.Achievements.hasRule(unlock.Achievement) {
+			return fmt.Errorf("emblems.unlocks.%s: unknown achievement %q", emblem, unlock.Achievement)
+		}
+	}
+
+	if c.Badge.Crop != "" && !contains(CropStrategies, c.Badge.Crop) {
+		return fmt.Errorf("badge.crop %q is not one of %v", c.Badge.Crop, CropStrategies)
+	}
+
+	if t := c.Badge.ContrastTarget; t != 0 && (t < 1 || t > 21) {
+		return fmt.Errorf("badge.contrast_target must be between 1 and 21")
+	}
+
+	if c.Badge.TopLanguages < 0 {
+		return fmt.Errorf("badge.top_languages must not be negative")
+	}
+
+	if w := c.Badge.Sparkline.Weeks; w != 0 && (w < MinSparklineWeeks || w > MaxSparklineWeeks) {
+		return fmt.Errorf("badge.sparkline.weeks must be between %d and %d", MinSparklineWeeks, MaxSparklineWeeks)
+	}
+	if src := c.Badge.Sparkline.Source; src != "" && src != SparklineCalendar && src != SparklineHistory {
+		return fmt.Errorf("badge.sparkline.source must be %q or %q", SparklineCalendar, SparklineHistory)
+	}
+
+	if err := c.Badge.Animation.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Output.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Serve.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Cache.validate(); err != nil {
+		return err
+	}
+
+	if err := c.PowerLevel.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Achievements.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// Validate checks weights, caps and the soft/pinnacle cap ordering
+func (p *PowerLevelConfig) Validate() error {
+	for key, weight := range p.Weights {
+		if !isMetric(key) {
+			return fmt.Errorf("power_level.weights: unknown metric %q", key)
+		}
+		if weight < 0 {
+			return fmt.Errorf("power_level.weights.%s must not be negative", key)
+		}
+	}
+
+	for key, limit := range p.Caps {
+		if !isMetric(key) {
+			return fmt.Errorf("power_level.caps: unknown metric %q", key)
+		}
+		if limit <= 0 {
+			return fmt.Errorf("power_level.caps.%s must be positive", key)
+		}
+	}
+
+	if p.SoftCap < 0 || p.PinnacleCap < 0 {
+		return fmt.Errorf("power_level.soft_cap and pinnacle_cap must not be negative")
+	}
+	if p.SoftCapRate < 0 || p.SoftCapRate > 1 {
+		return fmt.Errorf("power_level.soft_cap_rate must be between 0 and 1")
+	}
+	if p.SoftCap > 0 && p.PinnacleCap > 0 && p.SoftCap > p.PinnacleCap {
+		return fmt.Errorf("power_level.soft_cap (%d) must not exceed pinnacle_cap (%d)", p.SoftCap, p.PinnacleCap)
+	}
+
+	for i, tier := range p.Tiers {
+		if tier.Name == "" {
+			return fmt.Errorf("power_level.tiers[%d].name is required", i)
+		}
+		if i > 0 && tier.Min <= p.Tiers[i-1].Min {
+			return fmt.Errorf("power_level.tiers[%d].min must be greater than the previous tier's", i)
+		}
+		if tier.Accent != "" && !isHexColor(tier.Accent) {
+			return fmt.Errorf("power_level.tiers[%d].accent %q is not a #RRGGBB color", i, tier.Accent)
+		}
+		if tier.Glow != "" && !isHexColor(tier.Glow) {
+			return fmt.Errorf("power_level.tiers[%d].glow %q is not a #RRGGBB color", i, tier.Glow)
+		}
+		if tier.Icon != "" && !contains(TierIcons, tier.Icon) {
+			return fmt.Errorf("power_level.tiers[%d].icon %q is not one of %v", i, tier.Icon, TierIcons)
+		}
+	}
+
+	return nil
+}
+
+// validateSelection checks the strategy, weights, pinned date ranges,
+// crop offsets and unlocks
+func (e *EmblemsConfig) validateSelection() error {
+	if e.Strategy != "" && !contains(Strategies, e.Strategy) {
+		return fmt.Errorf("emblems.strategy %q is not one of %v", e.Strategy, Strategies)
+	}
+
+	for emblem, weight := range e.Weights {
+		if !contains(e.Rotation, emblem) {
+			return fmt.Errorf("emblems.weights: %s is not in the rotation", emblem)
+		}
+		if weight < 0 {
+			return fmt.Errorf("emblems.weights.%s must not be negative", emblem)
+		}
+	}
+
+	for i, pin := range e.Pinned {
+		if pin.Emblem == "" {
+			return fmt.Errorf("emblems.pinned[%d].emblem is required", i)
+		}
+		if _, err := time.Parse("01-02", pin.From); err != nil {
+			return fmt.Errorf("emblems.pinned[%d].from %q is not MM-DD", i, pin.From)
+		}
+		if _, err := time.Parse("01-02", pin.To); err != nil {
+			return fmt.Errorf("emblems.pinned[%d].to %q is not MM-DD", i, pin.To)
+		}
+	}
+
+	for emblem, offset := range e.CropOffsets {
+		if !contains(e.Hashes(), emblem) {
+			return fmt.Errorf("emblems.crop_offsets: %s is not a configured emblem", emblem)
+		}
+		for axis, v := range map[string]*float64{"x": offset.X, "y": offset.Y} {
+			if v != nil && (*v < 0 || *v > 1) {
+				return fmt.Errorf("emblems.crop_offsets.%s.%s must be between 0 and 1", emblem, axis)
+			}
+		}
+	}
+
+	for emblem, unlock := range e.Unlocks {
+		if !contains(e.Rotation, emblem) {
+			return fmt.Errorf("emblems.unlocks: %s is not in the rotation", emblem)
+		}
+		if unlock.MinPowerLevel < 0 {
+			return fmt.Errorf("emblems.unlocks.%s.min_power_level must not be negative", emblem)
+		}
+	}
+
+	return nil
+}
+
+// Hashes lists every emblem the config can select (rotation, fallback and
+// pinned emblems) once each, in that order
+func (e *EmblemsConfig) Hashes() []string {
+	var hashes []string
+	add := func(hash string) {
+		if hash != "" && !contains(hashes, hash) {
+			hashes = append(hashes, hash)
+		}
+	}
+	for _, hash := range e.Rotation {
+		add(hash)
+	}
+	add(e.Fallback)
+	for _, pin := range e.Pinned {
+		add(pin.Emblem)
+	}
+	return hashes
+}
+
+// hasRule reports whether a rule with the given ID is in effect
+func (a *AchievementsConfig) hasRule(id string) bool {
+	for _, rule := range a.RuleList() {
+		if rule.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks achievement rules reference known metrics and icons
+func (a *AchievementsConfig) Validate() error {
+	if a.MaxIcons < 0 {
+		return fmt.Errorf("achievements.max_icons must not be negative")
+	}
+
+	seen := make(map[string]bool)
+	for i, rule := range a.Rules {
+		if rule.ID == "" {
+			return fmt.Errorf("achievements.rules[%d].id is required", i)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("achievements.rules[%d]: duplicate id %q", i, rule.ID)
+		}
+		seen[rule.ID] = true
+		if !isMetric(rule.Metric) {
+			return fmt.Errorf("achievements.rules[%d]: unknown metric %q", i, rule.Metric)
+		}
+		if rule.Threshold <= 0 {
+			return fmt.Errorf("achievements.rules[%d].threshold must be positive", i)
+		}
+		if rule.Icon != "" && !contains(TierIcons, rule.Icon) {
+			return fmt.Errorf("achievements.rules[%d].icon %q is not one of %v", i, rule.Icon, TierIcons)
+		}
+	}
+
+	return nil
+}
+
+// isHexColor reports whether s is a "#RRGGBB" color
+func isHexColor(s string) bool {
+	if len(s) != 7 || s[0] != '#' {
+		return false
+	}
+	for _, c := range s[1:] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// contains reports whether list holds s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isMetric reports whether key is a known metric key
+func isMetric(key string) bool {
+	return contains(AllMetrics, key)
+}
+
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() *Config {
 	return &Config{
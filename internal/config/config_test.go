@@ -3,7 +3,9 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadValidConfig(t *testing.T) {
@@ -109,6 +111,460 @@ func TestValidateNoMetricsEnabled(t *testing.T) {
 	}
 }
 
+func TestValidateStreakMetricOnly(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Username = "testuser"
+	cfg.Metrics = MetricsConfig{CurrentStreak: true}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected streak-only metrics to be valid, got %v", err)
+	}
+}
+
+func TestValidateTooManyMetrics(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Username = "testuser"
+	cfg.Metrics.CurrentStreak = true
+	cfg.Metrics.LongestStreak = true
+	cfg.Metrics.MergedPullRequests = true
+	cfg.Metrics.Followers = true // 9 metrics enabled
+
+	if err := cfg.Validate(); err == nil {
+		t.Errorf("Expected validation error with more than %d metrics", MaxMetrics)
+	}
+}
+
+func TestValidateSparkline(t *testing.T) {
+	tests := []struct {
+		name      string
+		sparkline SparklineConfig
+		wantErr   bool
+	}{
+		{"defaults", SparklineConfig{Enabled: true}, false},
+		{"history source", SparklineConfig{Enabled: true, Weeks: 52, Source: SparklineHistory}, false},
+		{"too few weeks", SparklineConfig{Enabled: true, Weeks: 4}, true},
+		{"too many weeks", SparklineConfig{Enabled: true, Weeks: 60}, true},
+		{"unknown source", SparklineConfig{Enabled: true, Source: "commits"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Badge.Sparkline = tt.sparkline
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAnimation(t *testing.T) {
+	tests := []struct {
+		name      string
+		animation AnimationConfig
+		wantErr   bool
+	}{
+		{"defaults", AnimationConfig{Enabled: true}, false},
+		{"apng rotation", AnimationConfig{Enabled: true, Format: FormatAPNG, Effects: []string{EffectRotation}, Frames: 60, Duration: "6s"}, false},
+		{"unknown format", AnimationConfig{Enabled: true, Format: "webp"}, true},
+		{"unknown effect", AnimationConfig{Enabled: true, Effects: []string{"spin"}}, true},
+		{"too few frames", AnimationConfig{Enabled: true, Frames: 1}, true},
+		{"too many frames", AnimationConfig{Enabled: true, Frames: 500}, true},
+		{"bad duration", AnimationConfig{Enabled: true, Duration: "2"}, true},
+		{"negative duration", AnimationConfig{Enabled: true, Duration: "-1s"}, true},
+		{"frames too short", AnimationConfig{Enabled: true, Frames: 120, Duration: "1s"}, true},
+		{"frames too long", AnimationConfig{Enabled: true, Frames: 2, Duration: "200s"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Badge.Animation = tt.animation
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAnimationDefaults(t *testing.T) {
+	var a AnimationConfig
+	if got := a.FrameCount(); got != DefaultAnimationFrames {
+		t.Errorf("FrameCount() = %d, want %d", got, DefaultAnimationFrames)
+	}
+	if got := a.LoopDuration(); got != DefaultAnimationLoop {
+		t.Errorf("LoopDuration() = %v, want %v", got, DefaultAnimationLoop)
+	}
+	if got := a.EffectList(); len(got) != 2 || got[0] != EffectCountUp || got[1] != EffectShimmer {
+		t.Errorf("EffectList() = %v, want count-up and shimmer", got)
+	}
+	if got := a.OutputPath(); got != "badge.gif" {
+		t.Errorf("OutputPath() = %q, want badge.gif", got)
+	}
+
+	a = AnimationConfig{Format: FormatAPNG, Duration: "1500ms"}
+	if got := a.OutputPath(); got != "badge.apng" {
+		t.Errorf("OutputPath() = %q, want badge.apng", got)
+	}
+	if got := a.LoopDuration(); got != 1500*time.Millisecond {
+		t.Errorf("LoopDuration() = %v, want 1.5s", got)
+	}
+}
+
+func TestValidateOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  OutputConfig
+		wantErr bool
+	}{
+		{"defaults", OutputConfig{}, false},
+		{"quantized png", OutputConfig{Badge: "assets/badge.png", Compression: CompressionBest, Colors: 128}, false},
+		{"jpeg", OutputConfig{Badge: "badge.JPG", Quality: 80}, false},
+		{"unknown extension", OutputConfig{Badge: "badge.webp"}, true},
+		{"unknown compression", OutputConfig{Compression: "max"}, true},
+		{"too few colors", OutputConfig{Colors: 1}, true},
+		{"too many colors", OutputConfig{Colors: 512}, true},
+		{"colors on jpeg", OutputConfig{Badge: "badge.jpg", Colors: 64}, true},
+		{"quality out of range", OutputConfig{Badge: "badge.jpg", Quality: 101}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Output = tt.output
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServe(t *testing.T) {
+	tests := []struct {
+		name    string
+		serve   ServeConfig
+		wantErr bool
+	}{
+		{"defaults", ServeConfig{}, false},
+		{"allowlist", ServeConfig{Addr: "127.0.0.1:9000", TTL: "30m", Users: []string{"castrojo", "jeefy"}}, false},
+		{"anyone", ServeConfig{Users: []string{AnyUser}}, false},
+		{"bad ttl", ServeConfig{TTL: "hourly"}, true},
+		{"negative ttl", ServeConfig{TTL: "-1m"}, true},
+		{"bad username", ServeConfig{Users: []string{"../etc"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Serve = tt.serve
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	s := ServeConfig{}
+	if s.Address() != DefaultServeAddr || s.CacheTTL() != DefaultServeTTL {
+		t.Errorf("defaults = %q, %v", s.Address(), s.CacheTTL())
+	}
+}
+
+func TestValidateCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"default", "", DefaultStatsTTL, false},
+		{"hours", "12h", 12 * time.Hour, false},
+		{"always refetch", "0", 0, false},
+		{"not a duration", "daily", 0, true},
+		{"negative", "-1h", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Cache.TTL = tt.ttl
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && cfg.Cache.StatsTTL() != tt.want {
+				t.Errorf("StatsTTL() = %v, want %v", cfg.Cache.StatsTTL(), tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUsername(t *testing.T) {
+	tests := map[string]bool{
+		"castrojo":              true,
+		"mr-bobby":              true,
+		"A1":                    true,
+		"":                      false,
+		"-leading":              false,
+		"trailing-":             false,
+		"double--hy":            false,
+		"dot.name":              false,
+		strings.Repeat("a", 40): false,
+	}
+	for name, want := range tests {
+		if got := IsUsername(name); got != want {
+			t.Errorf("IsUsername(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestValidateAchievements(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []AchievementRule
+		wantErr bool
+	}{
+		{"valid rule", []AchievementRule{{ID: "r", Name: "Reviewer", Metric: MetricReviews, Threshold: 100}}, false},
+		{"missing id", []AchievementRule{{Metric: MetricReviews, Threshold: 100}}, true},
+		{"duplicate id", []AchievementRule{
+			{ID: "r", Metric: MetricReviews, Threshold: 1},
+			{ID: "r", Metric: MetricStars, Threshold: 1},
+		}, true},
+		{"unknown metric", []AchievementRule{{ID: "r", Metric: "karma", Threshold: 1}}, true},
+		{"zero threshold", []AchievementRule{{ID: "r", Metric: MetricStars}}, true},
+		{"unknown icon", []AchievementRule{{ID: "r", Metric: MetricStars, Threshold: 1, Icon: "trophy"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Achievements = AchievementsConfig{Enabled: true, Rules: tt.rules}
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEmblemSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		weights  map[string]float64
+		pinned   []PinnedEmblem
+		wantErr  bool
+	}{
+		{"defaults", "", nil, nil, false},
+		{"known strategy", StrategyShuffle, nil, nil, false},
+		{"unknown strategy", "random", nil, nil, true},
+		{"weights in rotation", StrategyWeighted, map[string]float64{"4052831236": 3}, nil, false},
+		{"weight not in rotation", StrategyWeighted, map[string]float64{"123": 1}, nil, true},
+		{"negative weight", StrategyWeighted, map[string]float64{"4052831236": -1}, nil, true},
+		{"pinned range", "", nil, []PinnedEmblem{{Emblem: "123", From: "12-20", To: "01-05"}}, false},
+		{"pinned leap day", "", nil, []PinnedEmblem{{Emblem: "123", From: "02-29", To: "02-29"}}, false},
+		{"pinned without emblem", "", nil, []PinnedEmblem{{From: "12-20", To: "12-31"}}, true},
+		{"pinned bad date", "", nil, []PinnedEmblem{{Emblem: "123", From: "2026-12-20", To: "12-31"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Emblems.Strategy = tt.strategy
+			cfg.Emblems.Weights = tt.weights
+			cfg.Emblems.Pinned = tt.pinned
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEmblemUnlocks(t *testing.T) {
+	tests := []struct {
+		name         string
+		unlocks      map[string]EmblemUnlock
+		achievements AchievementsConfig
+		wantErr      bool
+	}{
+		{"power level gate", map[string]EmblemUnlock{"4052831236": {MinPowerLevel: 1000}}, AchievementsConfig{}, false},
+		{"not in rotation", map[string]EmblemUnlock{"123": {MinPowerLevel: 1000}}, AchievementsConfig{}, true},
+		{"negative power level", map[string]EmblemUnlock{"4052831236": {MinPowerLevel: -1}}, AchievementsConfig{}, true},
+		{"achievement without achievements", map[string]EmblemUnlock{"4052831236": {Achievement: "reviewer"}}, AchievementsConfig{}, true},
+		{"achievement with default rules", map[string]EmblemUnlock{"4052831236": {Achievement: "reviewer"}}, AchievementsConfig{Enabled: true}, true},
+		{"default rule", map[string]EmblemUnlock{"4052831236": {Achievement: "reviews-100"}}, AchievementsConfig{Enabled: true}, false},
+		{"unknown achievement", map[string]EmblemUnlock{"4052831236": {Achievement: "reviewer"}}, AchievementsConfig{
+			Enabled: true,
+			Rules:   []AchievementRule{{ID: "stars", Metric: MetricStars, Threshold: 1}},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Emblems.Unlocks = tt.unlocks
+			cfg.Achievements = tt.achievements
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEmblemsHashes(t *testing.T) {
+	e := EmblemsConfig{
+		Rotation: []string{"1", "2", "1"},
+		Fallback: "2",
+		Pinned:   []PinnedEmblem{{Emblem: "3", From: "12-24", To: "12-26"}},
+	}
+	got := e.Hashes()
+	want := []string{"1", "2", "3"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Hashes() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateCrop(t *testing.T) {
+	half, over := 0.5, 1.5
+	tests := []struct {
+		name    string
+		crop    string
+		offsets map[string]CropOffset
+		wantErr bool
+	}{
+		{"default", "", nil, false},
+		{"entropy", CropEntropy, nil, false},
+		{"unknown strategy", "smart", nil, true},
+		{"offset for rotation emblem", "", map[string]CropOffset{"4052831236": {X: &half}}, false},
+		{"offset for fallback", "", map[string]CropOffset{"4052831236": {Y: &half}}, false},
+		{"offset for unknown emblem", "", map[string]CropOffset{"123": {X: &half}}, true},
+		{"offset out of range", "", map[string]CropOffset{"4052831236": {X: &over}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Badge.Crop = tt.crop
+			cfg.Emblems.CropOffsets = tt.offsets
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateContrastTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  float64
+		wantErr bool
+	}{
+		{"unset", 0, false},
+		{"AA", 4.5, false},
+		{"AAA", 7, false},
+		{"too low", 0.5, true},
+		{"too high", 30, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.Badge.ContrastTarget = tt.target
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePowerLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		pl      PowerLevelConfig
+		wantErr bool
+	}{
+		{"zero value", PowerLevelConfig{}, false},
+		{"weights and caps", PowerLevelConfig{
+			Weights: map[string]float64{MetricStars: 0.25, MetricFollowers: 0.1},
+			Caps:    map[string]float64{MetricStars: 500},
+		}, false},
+		{"unknown weight metric", PowerLevelConfig{Weights: map[string]float64{"karma": 1}}, true},
+		{"negative weight", PowerLevelConfig{Weights: map[string]float64{MetricCommits: -1}}, true},
+		{"zero cap", PowerLevelConfig{Caps: map[string]float64{MetricStars: 0}}, true},
+		{"soft cap above pinnacle", PowerLevelConfig{SoftCap: 2000, PinnacleCap: 1000}, true},
+		{"soft cap rate above one", PowerLevelConfig{SoftCapRate: 1.5}, true},
+		{"ascending tiers", PowerLevelConfig{Tiers: []TierConfig{
+			{Name: "Common", Min: 0}, {Name: "Exotic", Min: 2000, Accent: "#CEAE33", Icon: IconStar},
+		}}, false},
+		{"unordered tiers", PowerLevelConfig{Tiers: []TierConfig{
+			{Name: "Exotic", Min: 2000}, {Name: "Common", Min: 0},
+		}}, true},
+		{"unnamed tier", PowerLevelConfig{Tiers: []TierConfig{{Min: 0}}}, true},
+		{"bad tier color", PowerLevelConfig{Tiers: []TierConfig{{Name: "Rare", Glow: "blue"}}}, true},
+		{"unknown tier icon", PowerLevelConfig{Tiers: []TierConfig{{Name: "Rare", Icon: "hexagon"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Username = "testuser"
+			cfg.PowerLevel = tt.pl
+
+			err := cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMetricsEnabled(t *testing.T) {
+	m := MetricsConfig{
+		Commits:         true,
+		Reviews:         true,
+		LongestStreak:   true,
+		ActiveDaysRatio: true,
+	}
+
+	got := m.Enabled()
+	want := []string{MetricCommits, MetricReviews, MetricLongestStreak, MetricActiveDaysRatio}
+	if len(got) != len(want) {
+		t.Fatalf("Enabled() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Enabled()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
 func TestValidateEmptyEmblemRotation(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Username = "testuser"
@@ -131,6 +587,20 @@ func TestValidateEmptyEmblemID(t *testing.T) {
 	}
 }
 
+func TestValidateCustomEmblemPath(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Username = "testuser"
+	cfg.Emblems.Rotation = []string{"4052831236", "file:assets/emblems/banner.png", "https://example.com/banner.png"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected custom emblems to validate, got %v", err)
+	}
+
+	cfg.Emblems.Rotation = []string{"4052831236", "file:"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for empty file: path")
+	}
+}
+
 func TestValidateMissingFallback(t *testing.T) {
 	cfg := DefaultConfig()
 	cfg.Username = "testuser"
@@ -0,0 +1,125 @@
+package emblem
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"  // decode custom GIF artwork
+	_ "image/jpeg" // decode custom JPEG artwork
+	_ "image/png"  // decode custom PNG artwork
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/bungie"
+)
+
+// Custom artwork limits. The minimum matches Destiny's 474x96 banner; the
+// aspect range admits 16:9 detail art up to very wide banners, which the
+// badge crops to 800:162.
+const (
+	FilePrefix         = "file:"
+	MinCustomWidth     = 474
+	MinCustomHeight    = 96
+	MinCustomAspect    = 1.5
+	MaxCustomAspect    = 8.0
+	maxCustomImageSize = 20 << 20 // bytes
+)
+
+// IsCustom reports whether a rotation entry is user-supplied artwork
+// (a file: path or an http(s) URL) rather than a Bungie emblem
+func IsCustom(ref string) bool {
+	return strings.HasPrefix(ref, FilePrefix) ||
+		strings.HasPrefix(ref, "https://") ||
+		strings.HasPrefix(ref, "http://")
+}
+
+// FetchCustom reads or downloads custom artwork, validates it and writes it
+// to outputPath without re-encoding
+func FetchCustom(ref, outputPath string) error {
+	data, err := readCustom(ref)
+	if err != nil {
+		return err
+	}
+	if err := ValidateCustom(data); err != nil {
+		return fmt.Errorf("custom emblem %s: %w", ref, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write custom emblem: %w", err)
+	}
+	return nil
+}
+
+// CheckCustom reads or downloads custom artwork and validates it
+func CheckCustom(ref string) error {
+	data, err := readCustom(ref)
+	if err != nil {
+		return err
+	}
+	return ValidateCustom(data)
+}
+
+// ValidateCustom checks an image decodes and meets the resolution and
+// aspect ratio limits
+func ValidateCustom(data []byte) error {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("not a supported image (JPEG, PNG or GIF): %w", err)
+	}
+
+	if cfg.Width < MinCustomWidth || cfg.Height < MinCustomHeight {
+		return fmt.Errorf("%s is %dx%d, need at least %dx%d", format, cfg.Width, cfg.Height, MinCustomWidth, MinCustomHeight)
+	}
+	aspect := float64(cfg.Width) / float64(cfg.Height)
+	if aspect < MinCustomAspect || aspect > MaxCustomAspect {
+		return fmt.Errorf("%s is %dx%d (aspect %.2f:1), need between %.1f:1 and %.1f:1", format, cfg.Width, cfg.Height, aspect, MinCustomAspect, MaxCustomAspect)
+	}
+	return nil
+}
+
+// readCustom returns the raw bytes of a file: path or URL
+func readCustom(ref string) ([]byte, error) {
+	if path, ok := strings.CutPrefix(ref, FilePrefix); ok {
+		if path == "" {
+			return nil, fmt.Errorf("custom emblem %q has no path", ref)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read custom emblem: %w", err)
+		}
+		return data, nil
+	}
+
+	req, err := http.NewRequest("GET", ref, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom emblem URL: %w", err)
+	}
+	req.Header.Set("User-Agent", bungie.UserAgent)
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download custom emblem: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download custom emblem: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxCustomImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download custom emblem: %w", err)
+	}
+	if len(data) > maxCustomImageSize {
+		return nil, fmt.Errorf("custom emblem larger than %d MB", maxCustomImageSize>>20)
+	}
+	return data, nil
+}
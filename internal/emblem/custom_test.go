@@ -0,0 +1,121 @@
+package emblem
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func encodeTestPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatalf("Failed to encode test PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestIsCustom(t *testing.T) {
+	for ref, want := range map[string]bool{
+		"file:assets/banner.png":         true,
+		"https://example.com/banner.png": true,
+		"http://example.com/banner.png":  true,
+		"4052831236":                     false,
+		"A Crimson Cathedral":            false,
+	} {
+		if got := IsCustom(ref); got != want {
+			t.Errorf("IsCustom(%q) = %v, want %v", ref, got, want)
+		}
+	}
+}
+
+func TestValidateCustom(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"destiny banner", encodeTestPNG(t, 474, 96), false},
+		{"badge size", encodeTestPNG(t, 800, 162), false},
+		{"16:9 detail art", encodeTestPNG(t, 1920, 1080), false},
+		{"too small", encodeTestPNG(t, 400, 81), true},
+		{"too square", encodeTestPNG(t, 600, 600), true},
+		{"too wide", encodeTestPNG(t, 2000, 200), true},
+		{"not an image", []byte("hello"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCustom(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCustom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchCustomFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "banner.png")
+	data := encodeTestPNG(t, 800, 162)
+	if err := os.WriteFile(src, data, 0644); err != nil {
+		t.Fatalf("Failed to write banner: %v", err)
+	}
+
+	out := filepath.Join(dir, "data", "emblem.jpg")
+	if err := FetchCustom(FilePrefix+src, out); err != nil {
+		t.Fatalf("FetchCustom() error = %v", err)
+	}
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("output not written: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Error("custom artwork was re-encoded")
+	}
+
+	if err := FetchCustom(FilePrefix+filepath.Join(dir, "missing.png"), out); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestFetchCustomURL(t *testing.T) {
+	banner := encodeTestPNG(t, 948, 192)
+	small := encodeTestPNG(t, 100, 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/banner.png":
+			w.Write(banner)
+		case "/small.png":
+			w.Write(small)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr bool
+	}{
+		{"valid banner", server.URL + "/banner.png", false},
+		{"below minimum", server.URL + "/small.png", true},
+		{"not found", server.URL + "/missing.png", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "emblem.jpg")
+			err := FetchCustom(tt.url, out)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FetchCustom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -12,8 +12,7 @@ import (
 )
 
 const (
-	FallbackEmblem    = "4052831236" // "Activate ESCALATION" emblem
-	DefaultConfigPath = "data/emblem-config.json"
+	FallbackEmblem = "4052831236" // "Activate ESCALATION" emblem
 )
 
 // Config represents emblem-config.json structure
@@ -65,30 +64,7 @@ func loadConfig(path string) (*Config, error) {
 }
 
 // SelectEmblemFromConfig performs deterministic weekly emblem selection
-// from a config.EmblemsConfig structure
+// from a config.EmblemsConfig structure using its configured strategy
 func SelectEmblemFromConfig(cfg *config.EmblemsConfig) (string, error) {
-	if cfg == nil || len(cfg.Rotation) == 0 {
-		// Use fallback if config missing or rotation empty
-		if cfg != nil && cfg.Fallback != "" {
-			return cfg.Fallback, nil
-		}
-		return FallbackEmblem, nil
-	}
-
-	// Calculate ISO week in UTC (format: YYYY-Www, e.g., "2026-W06")
-	now := time.Now().UTC()
-	year, week := now.ISOWeek()
-	isoWeek := fmt.Sprintf("%d-W%02d", year, week)
-
-	// Generate SHA256 hash of ISO week string
-	hash := sha256.Sum256([]byte(isoWeek))
-
-	// Convert first 8 bytes of hash to uint64 (deterministic seed)
-	hashValue := binary.BigEndian.Uint64(hash[:8])
-
-	// Select emblem using modulo operation
-	index := int(hashValue % uint64(len(cfg.Rotation)))
-	selectedEmblem := cfg.Rotation[index]
-
-	return selectedEmblem, nil
+	return (&Selector{Config: cfg}).Select()
 }
@@ -0,0 +1,235 @@
+package emblem
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// Clock returns the current time; tests inject a fixed clock
+type Clock func() time.Time
+
+// Selector picks the weekly emblem from an EmblemsConfig
+type Selector struct {
+	Config *config.EmblemsConfig
+	// Username seeds the username and weighted strategies
+	Username string
+	// Clock defaults to time.Now
+	Clock Clock
+	// Stats gates emblems listed in emblems.unlocks; nil skips gating
+	Stats *Stats
+}
+
+// Stats is the progress that emblem unlocks are checked against
+type Stats struct {
+	PowerLevel int
+	// Achievements holds the IDs of unlocked triumphs
+	Achievements map[string]bool
+}
+
+// pickFunc returns an index into rotation for the given week
+type pickFunc func(rotation []string, week weekInfo, s *Selector) int
+
+// strategies maps strategy names to their pick functions
+var strategies = map[string]pickFunc{
+	config.StrategyWeeklyHash: pickWeeklyHash,
+	config.StrategyRoundRobin: pickRoundRobin,
+	config.StrategyShuffle:    pickShuffle,
+	config.StrategyUsername:   pickUsername,
+	config.StrategyWeighted:   pickWeighted,
+}
+
+// weekInfo describes the week being selected for
+type weekInfo struct {
+	now   time.Time // UTC
+	iso   string    // "2026-W06"
+	index int64     // weeks since the Monday 1970-01-05, for cycle math
+}
+
+// Select returns the emblem for the current week: a pinned emblem when the
+// date falls in a pinned range, otherwise the configured strategy's pick
+// among unlocked emblems. An empty or fully locked rotation yields the
+// fallback.
+func (s *Selector) Select() (string, error) {
+	cfg := s.Config
+	if cfg == nil {
+		return FallbackEmblem, nil
+	}
+
+	week := newWeekInfo(s.now())
+	if pinned, ok := pinnedEmblem(cfg.Pinned, week.now); ok {
+		return pinned, nil
+	}
+
+	rotation := s.unlocked()
+	if len(rotation) == 0 {
+		if cfg.Fallback != "" {
+			return cfg.Fallback, nil
+		}
+		return FallbackEmblem, nil
+	}
+
+	name := cfg.Strategy
+	if name == "" {
+		name = config.StrategyWeeklyHash
+	}
+	pick, ok := strategies[name]
+	if !ok {
+		return "", fmt.Errorf("unknown emblem strategy %q", name)
+	}
+
+	return rotation[pick(rotation, week, s)], nil
+}
+
+// unlocked filters the rotation down to emblems the stats have earned
+func (s *Selector) unlocked() []string {
+	if s.Stats == nil || len(s.Config.Unlocks) == 0 {
+		return s.Config.Rotation
+	}
+
+	var rotation []string
+	for _, emblem := range s.Config.Rotation {
+		if s.Stats.Unlocked(s.Config.Unlocks[emblem]) {
+			rotation = append(rotation, emblem)
+		}
+	}
+	return rotation
+}
+
+// Unlocked reports whether the stats meet an emblem's requirements
+func (st *Stats) Unlocked(req config.EmblemUnlock) bool {
+	if st.PowerLevel < req.MinPowerLevel {
+		return false
+	}
+	return req.Achievement == "" || st.Achievements[req.Achievement]
+}
+
+func (s *Selector) now() time.Time {
+	if s.Clock != nil {
+		return s.Clock().UTC()
+	}
+	return time.Now().UTC()
+}
+
+func newWeekInfo(now time.Time) weekInfo {
+	year, week := now.ISOWeek()
+	epochMonday := time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return weekInfo{
+		now:   now,
+		iso:   fmt.Sprintf("%d-W%02d", year, week),
+		index: int64(day.Sub(epochMonday).Hours()/24) / 7,
+	}
+}
+
+// seed hashes the parts into a deterministic 64-bit value
+func seed(parts ...string) uint64 {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return binary.BigEndian.Uint64(h.Sum(nil)[:8])
+}
+
+// pickWeeklyHash is the original selection: SHA256 of the ISO week, modulo
+func pickWeeklyHash(rotation []string, week weekInfo, _ *Selector) int {
+	hash := sha256.Sum256([]byte(week.iso))
+	return int(binary.BigEndian.Uint64(hash[:8]) % uint64(len(rotation)))
+}
+
+// pickRoundRobin advances one emblem per week
+func pickRoundRobin(rotation []string, week weekInfo, _ *Selector) int {
+	return int(week.index % int64(len(rotation)))
+}
+
+// pickUsername is the weekly hash seeded by username, so users differ
+func pickUsername(rotation []string, week weekInfo, s *Selector) int {
+	return int(seed(week.iso, s.Username) % uint64(len(rotation)))
+}
+
+// pickShuffle walks a fresh permutation of the rotation every len(rotation)
+// weeks, so each emblem shows once per cycle. A cycle never starts with the
+// emblem that ended the previous one.
+func pickShuffle(rotation []string, week weekInfo, s *Selector) int {
+	n := int64(len(rotation))
+	if n <= 2 {
+		// Only strict alternation avoids back-to-back repeats
+		return pickRoundRobin(rotation, week, s)
+	}
+
+	cycle := week.index / n
+	perm := cyclePermutation(len(rotation), cycle, s.Username)
+
+	// Swapping the first two never touches the last slot when n > 2,
+	// so the previous cycle's ending is unaffected by its own swap
+	prev := cyclePermutation(len(rotation), cycle-1, s.Username)
+	if perm[0] == prev[n-1] {
+		perm[0], perm[1] = perm[1], perm[0]
+	}
+	return perm[week.index%n]
+}
+
+// cyclePermutation is a Fisher-Yates shuffle of 0..n-1 seeded by cycle
+func cyclePermutation(n int, cycle int64, username string) []int {
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	state := seed(fmt.Sprintf("cycle-%d", cycle), username)
+	for i := n - 1; i > 0; i-- {
+		// xorshift64 keeps the sequence deterministic and dependency-free
+		state ^= state << 13
+		state ^= state >> 7
+		state ^= state << 17
+		j := int(state % uint64(i+1))
+		perm[i], perm[j] = perm[j], perm[i]
+	}
+	return perm
+}
+
+// pickWeighted draws from the rotation in proportion to emblems.weights
+// (default 1 each), seeded by week and username
+func pickWeighted(rotation []string, week weekInfo, s *Selector) int {
+	weights := make([]float64, len(rotation))
+	var total float64
+	for i, emblem := range rotation {
+		weights[i] = 1
+		if w, ok := s.Config.Weights[emblem]; ok {
+			weights[i] = w
+		}
+		total += weights[i]
+	}
+	if total == 0 {
+		return pickUsername(rotation, week, s)
+	}
+
+	// Map the seed onto [0, total)
+	target := float64(seed(week.iso, s.Username)>>11) / float64(1<<53) * total
+	for i, w := range weights {
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	return len(rotation) - 1
+}
+
+// pinnedEmblem returns the first pinned emblem whose MM-DD range contains now
+func pinnedEmblem(pins []config.PinnedEmblem, now time.Time) (string, bool) {
+	today := now.Format("01-02")
+	for _, pin := range pins {
+		if pin.From <= pin.To {
+			if today >= pin.From && today <= pin.To {
+				return pin.Emblem, true
+			}
+		} else if today >= pin.From || today <= pin.To {
+			// Range wraps the new year
+			return pin.Emblem, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,259 @@
+package emblem
+
+import (
+	"testing"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+var testRotation = []string{"4052831236", "1901885391", "1661191194", "2962058744", "1409726931"}
+
+func fixedClock(year int, month time.Month, day int) Clock {
+	return func() time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC)
+	}
+}
+
+// weeklyPicks selects once per week for n weeks starting at start
+func weeklyPicks(t *testing.T, cfg *config.EmblemsConfig, username string, start time.Time, n int) []string {
+	t.Helper()
+	var picks []string
+	for i := 0; i < n; i++ {
+		day := start.AddDate(0, 0, 7*i)
+		s := &Selector{Config: cfg, Username: username, Clock: func() time.Time { return day }}
+		pick, err := s.Select()
+		if err != nil {
+			t.Fatalf("Select() failed: %v", err)
+		}
+		picks = append(picks, pick)
+	}
+	return picks
+}
+
+func TestSelectorWeeklyHashMatchesLegacy(t *testing.T) {
+	cfg := &config.EmblemsConfig{Rotation: testRotation}
+	explicit := &config.EmblemsConfig{Rotation: testRotation, Strategy: config.StrategyWeeklyHash}
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	a := weeklyPicks(t, cfg, "", start, 20)
+	b := weeklyPicks(t, explicit, "someone", start, 20)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("week %d: default %s != weekly-hash %s", i, a[i], b[i])
+		}
+	}
+}
+
+func TestSelectorRoundRobin(t *testing.T) {
+	cfg := &config.EmblemsConfig{Rotation: testRotation, Strategy: config.StrategyRoundRobin}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	picks := weeklyPicks(t, cfg, "", start, 2*len(testRotation))
+
+	first := -1
+	for i, e := range testRotation {
+		if e == picks[0] {
+			first = i
+		}
+	}
+	for i, pick := range picks {
+		want := testRotation[(first+i)%len(testRotation)]
+		if pick != want {
+			t.Errorf("week %d: got %s, want %s", i, pick, want)
+		}
+	}
+
+	// Every day of the same week picks the same emblem
+	monday := weeklyPicks(t, cfg, "", start, 1)[0]
+	sunday := weeklyPicks(t, cfg, "", start.AddDate(0, 0, 6), 1)[0]
+	if monday != sunday {
+		t.Errorf("Monday %s and Sunday %s differ within one week", monday, sunday)
+	}
+}
+
+func TestSelectorShuffleCycles(t *testing.T) {
+	cfg := &config.EmblemsConfig{Rotation: testRotation, Strategy: config.StrategyShuffle}
+	n := len(testRotation)
+
+	// Align to a cycle boundary so each chunk of n weeks is one cycle
+	epoch := time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)
+	start := epoch.AddDate(0, 0, 7*n*600)
+	picks := weeklyPicks(t, cfg, "octocat", start, 10*n)
+
+	for c := 0; c < 10; c++ {
+		seen := map[string]bool{}
+		for _, pick := range picks[c*n : (c+1)*n] {
+			if seen[pick] {
+				t.Fatalf("cycle %d repeats %s: %v", c, pick, picks[c*n:(c+1)*n])
+			}
+			seen[pick] = true
+		}
+	}
+	for i := 1; i < len(picks); i++ {
+		if picks[i] == picks[i-1] {
+			t.Errorf("weeks %d and %d both pick %s", i-1, i, picks[i])
+		}
+	}
+}
+
+func TestSelectorUsernameSeed(t *testing.T) {
+	cfg := &config.EmblemsConfig{Rotation: testRotation, Strategy: config.StrategyUsername}
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	alice := weeklyPicks(t, cfg, "alice", start, 20)
+	again := weeklyPicks(t, cfg, "alice", start, 20)
+	bob := weeklyPicks(t, cfg, "bob", start, 20)
+
+	differ := false
+	for i := range alice {
+		if alice[i] != again[i] {
+			t.Fatalf("week %d not deterministic: %s != %s", i, alice[i], again[i])
+		}
+		if alice[i] != bob[i] {
+			differ = true
+		}
+	}
+	if !differ {
+		t.Error("different usernames picked identical sequences")
+	}
+}
+
+func TestSelectorWeighted(t *testing.T) {
+	cfg := &config.EmblemsConfig{
+		Rotation: testRotation,
+		Strategy: config.StrategyWeighted,
+		Weights: map[string]float64{
+			testRotation[0]: 10,
+			testRotation[1]: 0,
+		},
+	}
+	start := time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)
+	picks := weeklyPicks(t, cfg, "octocat", start, 300)
+
+	counts := map[string]int{}
+	for _, pick := range picks {
+		counts[pick]++
+	}
+	if counts[testRotation[1]] != 0 {
+		t.Errorf("zero-weight emblem picked %d times", counts[testRotation[1]])
+	}
+	// Weight 10 of 13 should dominate the default-weight emblems
+	for _, e := range testRotation[2:] {
+		if counts[testRotation[0]] <= counts[e] {
+			t.Errorf("heavy emblem picked %d times, %s picked %d", counts[testRotation[0]], e, counts[e])
+		}
+	}
+}
+
+func TestSelectorPinned(t *testing.T) {
+	cfg := &config.EmblemsConfig{
+		Rotation: testRotation,
+		Strategy: config.StrategyRoundRobin,
+		Pinned: []config.PinnedEmblem{
+			{Emblem: "1111111111", From: "12-20", To: "01-05"},
+			{Emblem: "2222222222", From: "07-04", To: "07-04"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		clock  Clock
+		pinned string
+	}{
+		{"inside wrapped range before new year", fixedClock(2026, 12, 24), "1111111111"},
+		{"inside wrapped range after new year", fixedClock(2027, 1, 5), "1111111111"},
+		{"just after wrapped range", fixedClock(2027, 1, 6), ""},
+		{"single day", fixedClock(2026, 7, 4), "2222222222"},
+		{"outside", fixedClock(2026, 7, 5), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Selector{Config: cfg, Clock: tt.clock}
+			got, err := s.Select()
+			if err != nil {
+				t.Fatalf("Select() failed: %v", err)
+			}
+			if tt.pinned != "" && got != tt.pinned {
+				t.Errorf("got %s, want pinned %s", got, tt.pinned)
+			}
+			if tt.pinned == "" && (got == "1111111111" || got == "2222222222") {
+				t.Errorf("got pinned emblem %s outside its range", got)
+			}
+		})
+	}
+}
+
+func TestSelectorUnknownStrategy(t *testing.T) {
+	s := &Selector{
+		Config: &config.EmblemsConfig{Rotation: testRotation, Strategy: "random"},
+		Clock:  fixedClock(2026, 2, 9),
+	}
+	if _, err := s.Select(); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
+func TestSelectorUnlocks(t *testing.T) {
+	cfg := &config.EmblemsConfig{
+		Rotation: []string{"1000000001", "1000000002", "1000000003"},
+		Fallback: "4052831236",
+		Strategy: config.StrategyRoundRobin,
+		Unlocks: map[string]config.EmblemUnlock{
+			"1000000002": {MinPowerLevel: 1000},
+			"1000000003": {Achievement: "reviewer"},
+		},
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		stats *Stats
+		want  map[string]bool
+	}{
+		{"nil stats skips gating", nil, map[string]bool{"1000000001": true, "1000000002": true, "1000000003": true}},
+		{"power level only", &Stats{PowerLevel: 1200}, map[string]bool{"1000000001": true, "1000000002": true}},
+		{"achievement only", &Stats{PowerLevel: 10, Achievements: map[string]bool{"reviewer": true}}, map[string]bool{"1000000001": true, "1000000003": true}},
+		{"nothing earned", &Stats{}, map[string]bool{"1000000001": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seen := map[string]bool{}
+			for i := 0; i < 6; i++ {
+				day := start.AddDate(0, 0, 7*i)
+				s := &Selector{Config: cfg, Stats: tt.stats, Clock: func() time.Time { return day }}
+				pick, err := s.Select()
+				if err != nil {
+					t.Fatalf("Select() failed: %v", err)
+				}
+				seen[pick] = true
+			}
+			if len(seen) != len(tt.want) {
+				t.Errorf("picked %v, want %v", seen, tt.want)
+			}
+			for pick := range seen {
+				if !tt.want[pick] {
+					t.Errorf("picked locked emblem %s", pick)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorAllLocked(t *testing.T) {
+	cfg := &config.EmblemsConfig{
+		Rotation: []string{"1000000001"},
+		Fallback: "4052831236",
+		Unlocks:  map[string]config.EmblemUnlock{"1000000001": {MinPowerLevel: 500}},
+	}
+	s := &Selector{Config: cfg, Stats: &Stats{PowerLevel: 499}, Clock: fixedClock(2026, 2, 9)}
+
+	got, err := s.Select()
+	if err != nil {
+		t.Fatalf("Select() failed: %v", err)
+	}
+	if got != "4052831236" {
+		t.Errorf("got %s, want fallback 4052831236", got)
+	}
+}
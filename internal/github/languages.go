@@ -0,0 +1,69 @@
+package github
+
+import "sort"
+
+// TopLanguages is how many languages FetchStats keeps in Stats.Languages
+const TopLanguages = 8
+
+// Language is one entry of the top languages breakdown
+type Language struct {
+	Name  string  `json:"name"`
+	Color string  `json:"color,omitempty"` // GitHub linguist color, e.g. "#00ADD8"
+	Size  int64   `json:"size"`            // bytes of code across repositories
+	Share float64 `json:"share"`           // fraction of all counted bytes (0-1)
+}
+
+// languageConnection is the GraphQL shape of a repository's languages
+type languageConnection struct {
+	Edges []struct {
+		Size int64 `json:"size"`
+		Node struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"node"`
+	} `json:"edges"`
+}
+
+// aggregateLanguages sums language sizes across repositories and returns the
+// top n by size. Shares are relative to all counted bytes, not just the top n.
+func aggregateLanguages(repos []languageConnection, n int) []Language {
+	byName := make(map[string]*Language)
+	var total int64
+	for _, repo := range repos {
+		for _, edge := range repo.Edges {
+			if edge.Node.Name == "" || edge.Size <= 0 {
+				continue
+			}
+			lang, ok := byName[edge.Node.Name]
+			if !ok {
+				lang = &Language{Name: edge.Node.Name, Color: edge.Node.Color}
+				byName[edge.Node.Name] = lang
+			}
+			lang.Size += edge.Size
+			total += edge.Size
+		}
+	}
+
+	if total == 0 {
+		return nil
+	}
+
+	languages := make([]Language, 0, len(byName))
+	for _, lang := range byName {
+		lang.Share = float64(lang.Size) / float64(total)
+		languages = append(languages, *lang)
+	}
+
+	// Largest first, name as a tie-breaker for deterministic output
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Size != languages[j].Size {
+			return languages[i].Size > languages[j].Size
+		}
+		return languages[i].Name < languages[j].Name
+	})
+
+	if len(languages) > n {
+		languages = languages[:n]
+	}
+	return languages
+}
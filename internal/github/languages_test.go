@@ -0,0 +1,51 @@
+package github
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAggregateLanguages(t *testing.T) {
+	reposJSON := `[
+		{"edges": [
+			{"size": 6000, "node": {"name": "Go", "color": "#00ADD8"}},
+			{"size": 1000, "node": {"name": "Shell", "color": "#89e051"}}
+		]},
+		{"edges": [
+			{"size": 2000, "node": {"name": "Go", "color": "#00ADD8"}},
+			{"size": 1000, "node": {"name": "Makefile", "color": "#427819"}}
+		]},
+		{"edges": []}
+	]`
+
+	var repos []languageConnection
+	if err := json.Unmarshal([]byte(reposJSON), &repos); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+
+	languages := aggregateLanguages(repos, 2)
+	if len(languages) != 2 {
+		t.Fatalf("Expected 2 languages, got %d", len(languages))
+	}
+
+	if languages[0].Name != "Go" || languages[0].Size != 8000 {
+		t.Errorf("Expected Go with 8000 bytes first, got %+v", languages[0])
+	}
+	if languages[0].Share != 0.8 {
+		t.Errorf("Expected Go share 0.8, got %v", languages[0].Share)
+	}
+	if languages[0].Color != "#00ADD8" {
+		t.Errorf("Expected Go color #00ADD8, got %s", languages[0].Color)
+	}
+
+	// Makefile and Shell tie at 1000 bytes; name breaks the tie
+	if languages[1].Name != "Makefile" {
+		t.Errorf("Expected Makefile second, got %s", languages[1].Name)
+	}
+}
+
+func TestAggregateLanguagesEmpty(t *testing.T) {
+	if languages := aggregateLanguages(nil, TopLanguages); languages != nil {
+		t.Errorf("Expected nil for no repositories, got %+v", languages)
+	}
+}
@@ -19,8 +19,50 @@ type Stats struct {
 	Issues        int    `json:"issues"`
 	Reviews       int    `json:"reviews"`
 	StarsReceived int    `json:"stars_received"`
+
+	// Consistency metrics derived from the contribution calendar
+	CurrentStreak   int     `json:"current_streak"`
+	LongestStreak   int     `json:"longest_streak"`
+	ActiveDaysRatio float64 `json:"active_days_ratio"`
+
+	// Contributions per calendar week this year, oldest first (for sparklines)
+	WeeklyContributions []int `json:"weekly_contributions,omitempty"`
+
+	// Additional profile metrics
+	MergedPullRequests      int `json:"merged_pull_requests"`     // all-time merged PRs authored
+	RepositoriesContributed int `json:"repositories_contributed"` // repos with commits this year
+	Followers               int `json:"followers"`
+	DiscussionAnswers       int `json:"discussion_answers"` // all-time accepted answers
+	Gists                   int `json:"gists"`
+
+	// Top languages by code size across owned and contributed repositories
+	Languages []Language `json:"languages,omitempty"`
 }
 
+// statsQuery fetches every metric in a single GraphQL round trip
+const statsQuery = `query($username: String!, $from: DateTime!, $to: DateTime!) {
+  user(login: $username) {
+    contributionsCollection(from: $from, to: $to) {
+      totalCommitContributions
+      totalPullRequestContributions
+      totalIssueContributions
+      totalPullRequestReviewContributions
+      totalRepositoriesWithContributedCommits
+      contributionCalendar { weeks { contributionDays { date contributionCount } } }
+    }
+    repositories(ownerAffiliations: OWNER, first: 100) {
+      nodes { stargazerCount languages(first: 10, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name color } } } }
+    }
+    repositoriesContributedTo(first: 100, contributionTypes: [COMMIT, PULL_REQUEST]) {
+      nodes { languages(first: 10, orderBy: {field: SIZE, direction: DESC}) { edges { size node { name color } } } }
+    }
+    pullRequests(states: MERGED) { totalCount }
+    followers { totalCount }
+    repositoryDiscussionComments(onlyAnswers: true) { totalCount }
+    gists { totalCount }
+  }
+}`
+
 // GraphQL response structures (nested for JSON unmarshaling)
 type graphQLResponse struct {
 	Data struct {
@@ -30,16 +72,37 @@ type graphQLResponse struct {
 				TotalPullRequestContributions       int `json:"totalPullRequestContributions"`
 				TotalIssueContributions             int `json:"totalIssueContributions"`
 				TotalPullRequestReviewContributions int `json:"totalPullRequestReviewContributions"`
+				TotalRepositoriesWithContributed    int `json:"totalRepositoriesWithContributedCommits"`
+				ContributionCalendar                struct {
+					Weeks []struct {
+						ContributionDays []contributionDay `json:"contributionDays"`
+					} `json:"weeks"`
+				} `json:"contributionCalendar"`
 			} `json:"contributionsCollection"`
 			Repositories struct {
 				Nodes []struct {
-					StargazerCount int `json:"stargazerCount"`
+					StargazerCount int                `json:"stargazerCount"`
+					Languages      languageConnection `json:"languages"`
 				} `json:"nodes"`
 			} `json:"repositories"`
+			RepositoriesContributedTo struct {
+				Nodes []struct {
+					Languages languageConnection `json:"languages"`
+				} `json:"nodes"`
+			} `json:"repositoriesContributedTo"`
+			PullRequests                 totalCount `json:"pullRequests"`
+			Followers                    totalCount `json:"followers"`
+			RepositoryDiscussionComments totalCount `json:"repositoryDiscussionComments"`
+			Gists                        totalCount `json:"gists"`
 		} `json:"user"`
 	} `json:"data"`
 }
 
+// totalCount is the GraphQL connection shape used for simple counters
+type totalCount struct {
+	TotalCount int `json:"totalCount"`
+}
+
 // FetchStats queries GitHub GraphQL API for user contribution stats
 // Requires GITHUB_TOKEN env var
 // If username is empty, falls back to GITHUB_ACTOR env var
@@ -66,7 +129,7 @@ func FetchStats(username string, client *http.Client) (*Stats, error) {
 
 	// Build GraphQL query
 	query := map[string]interface{}{
-		"query": "query($username: String!, $from: DateTime!, $to: DateTime!) { user(login: $username) { contributionsCollection(from: $from, to: $to) { totalCommitContributions totalPullRequestContributions totalIssueContributions totalPullRequestReviewContributions } repositories(ownerAffiliations: OWNER, first: 100) { nodes { stargazerCount } } } }",
+		"query": statsQuery,
 		"variables": map[string]string{
 			"username": username,
 			"from":     yearStart,
@@ -124,6 +187,24 @@ func FetchStats(username string, client *http.Client) (*Stats, error) {
 		totalStars += repo.StargazerCount
 	}
 
+	// Aggregate language sizes across owned and contributed repositories
+	var connections []languageConnection
+	for _, repo := range gqlResp.Data.User.Repositories.Nodes {
+		connections = append(connections, repo.Languages)
+	}
+	for _, repo := range gqlResp.Data.User.RepositoriesContributedTo.Nodes {
+		connections = append(connections, repo.Languages)
+	}
+
+	// Flatten the contribution calendar and derive streak metrics
+	var days []contributionDay
+	var weeks [][]contributionDay
+	for _, week := range gqlResp.Data.User.ContributionsCollection.ContributionCalendar.Weeks {
+		days = append(days, week.ContributionDays...)
+		weeks = append(weeks, week.ContributionDays)
+	}
+	streaks := computeStreaks(days, now)
+
 	// Transform to Stats struct (equivalent to process-stats.sh)
 	stats := &Stats{
 		Year:          currentYear,
@@ -133,6 +214,20 @@ func FetchStats(username string, client *http.Client) (*Stats, error) {
 		Issues:        gqlResp.Data.User.ContributionsCollection.TotalIssueContributions,
 		Reviews:       gqlResp.Data.User.ContributionsCollection.TotalPullRequestReviewContributions,
 		StarsReceived: totalStars,
+
+		CurrentStreak:   streaks.Current,
+		LongestStreak:   streaks.Longest,
+		ActiveDaysRatio: streaks.ActiveDaysRatio,
+
+		WeeklyContributions: weeklyTotals(weeks, now),
+
+		MergedPullRequests:      gqlResp.Data.User.PullRequests.TotalCount,
+		RepositoriesContributed: gqlResp.Data.User.ContributionsCollection.TotalRepositoriesWithContributed,
+		Followers:               gqlResp.Data.User.Followers.TotalCount,
+		DiscussionAnswers:       gqlResp.Data.User.RepositoryDiscussionComments.TotalCount,
+		Gists:                   gqlResp.Data.User.Gists.TotalCount,
+
+		Languages: aggregateLanguages(connections, TopLanguages),
 	}
 
 	return stats, nil
@@ -2,10 +2,12 @@ package github
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestStatsJSONMarshaling(t *testing.T) {
@@ -128,6 +130,138 @@ func TestFetchStats_Success(t *testing.T) {
 	}
 }
 
+// TestFetchStats_AdditionalMetrics tests the extra profile counters parsed from the same query
+func TestFetchStats_AdditionalMetrics(t *testing.T) {
+	mockResponse := `{
+		"data": {
+			"user": {
+				"contributionsCollection": {
+					"totalCommitContributions": 1,
+					"totalRepositoriesWithContributedCommits": 12
+				},
+				"repositories": {"nodes": []},
+				"pullRequests": {"totalCount": 87},
+				"followers": {"totalCount": 340},
+				"repositoryDiscussionComments": {"totalCount": 9},
+				"gists": {"totalCount": 4}
+			}
+		}
+	}`
+
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		gotQuery = body.Query
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	os.Setenv("GITHUB_TOKEN", "test-token")
+	defer os.Unsetenv("GITHUB_TOKEN")
+
+	client := &http.Client{
+		Transport: &mockTransport{
+			handler: func(req *http.Request) (*http.Response, error) {
+				req.URL.Scheme = "http"
+				req.URL.Host = server.URL[7:]
+				return http.DefaultTransport.RoundTrip(req)
+			},
+		},
+	}
+
+	stats, err := FetchStats("testuser", client)
+	if err != nil {
+		t.Fatalf("FetchStats() failed: %v", err)
+	}
+
+	if gotQuery != statsQuery {
+		t.Error("Expected all metrics to be requested in a single query")
+	}
+	if stats.MergedPullRequests != 87 {
+		t.Errorf("Expected MergedPullRequests=87, got %d", stats.MergedPullRequests)
+	}
+	if stats.RepositoriesContributed != 12 {
+		t.Errorf("Expected RepositoriesContributed=12, got %d", stats.RepositoriesContributed)
+	}
+	if stats.Followers != 340 {
+		t.Errorf("Expected Followers=340, got %d", stats.Followers)
+	}
+	if stats.DiscussionAnswers != 9 {
+		t.Errorf("Expected DiscussionAnswers=9, got %d", stats.DiscussionAnswers)
+	}
+	if stats.Gists != 4 {
+		t.Errorf("Expected Gists=4, got %d", stats.Gists)
+	}
+}
+
+// TestFetchStats_ContributionCalendar tests streak metrics derived from the calendar
+func TestFetchStats_ContributionCalendar(t *testing.T) {
+	// Three active days ending yesterday, with an empty today
+	now := time.Now().UTC()
+	day := func(offset int) string {
+		return now.AddDate(0, 0, offset).Format("2006-01-02")
+	}
+	mockResponse := fmt.Sprintf(`{
+		"data": {
+			"user": {
+				"contributionsCollection": {
+					"totalCommitContributions": 5,
+					"contributionCalendar": {
+						"weeks": [
+							{"contributionDays": [
+								{"date": %q, "contributionCount": 0},
+								{"date": %q, "contributionCount": 2},
+								{"date": %q, "contributionCount": 1},
+								{"date": %q, "contributionCount": 2},
+								{"date": %q, "contributionCount": 0}
+							]}
+						]
+					}
+				},
+				"repositories": {"nodes": []}
+			}
+		}
+	}`, day(-4), day(-3), day(-2), day(-1), day(0))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	os.Setenv("GITHUB_TOKEN", "test-token")
+	defer os.Unsetenv("GITHUB_TOKEN")
+
+	client := &http.Client{
+		Transport: &mockTransport{
+			handler: func(req *http.Request) (*http.Response, error) {
+				req.URL.Scheme = "http"
+				req.URL.Host = server.URL[7:]
+				return http.DefaultTransport.RoundTrip(req)
+			},
+		},
+	}
+
+	stats, err := FetchStats("testuser", client)
+	if err != nil {
+		t.Fatalf("FetchStats() failed: %v", err)
+	}
+
+	if stats.CurrentStreak != 3 {
+		t.Errorf("Expected CurrentStreak=3, got %d", stats.CurrentStreak)
+	}
+	if stats.LongestStreak != 3 {
+		t.Errorf("Expected LongestStreak=3, got %d", stats.LongestStreak)
+	}
+	if stats.ActiveDaysRatio != 0.6 {
+		t.Errorf("Expected ActiveDaysRatio=0.6, got %v", stats.ActiveDaysRatio)
+	}
+}
+
 // TestFetchStats_RateLimitHeader tests rate limit header logging
 func TestFetchStats_RateLimitHeader(t *testing.T) {
 	mockResponse := `{
@@ -0,0 +1,92 @@
+package github
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// contributionDay is a single day of the GitHub contribution calendar
+type contributionDay struct {
+	Date              string `json:"date"`
+	ContributionCount int    `json:"contributionCount"`
+}
+
+// streakStats holds consistency metrics derived from the contribution calendar
+type streakStats struct {
+	Current         int
+	Longest         int
+	ActiveDaysRatio float64
+}
+
+// computeStreaks derives current streak, longest streak and active-days ratio
+// from calendar days up to and including today (UTC).
+// The current streak tolerates an empty today, since the day isn't over yet.
+func computeStreaks(days []contributionDay, today time.Time) streakStats {
+	todayStr := today.UTC().Format("2006-01-02")
+
+	// Keep only days that have already started, in chronological order
+	var past []contributionDay
+	for _, day := range days {
+		if day.Date <= todayStr {
+			past = append(past, day)
+		}
+	}
+	sort.Slice(past, func(i, j int) bool { return past[i].Date < past[j].Date })
+
+	var stats streakStats
+	if len(past) == 0 {
+		return stats
+	}
+
+	// Longest streak and active days in a single forward pass
+	run, active := 0, 0
+	for _, day := range past {
+		if day.ContributionCount > 0 {
+			run++
+			active++
+			if run > stats.Longest {
+				stats.Longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+
+	// Current streak walks backwards from today, skipping an empty today
+	end := len(past) - 1
+	if past[end].ContributionCount == 0 && past[end].Date == todayStr {
+		end--
+	}
+	for i := end; i >= 0 && past[i].ContributionCount > 0; i-- {
+		stats.Current++
+	}
+
+	// Round to 3 decimals to keep stats.json stable and readable
+	ratio := float64(active) / float64(len(past))
+	stats.ActiveDaysRatio = math.Round(ratio*1000) / 1000
+
+	return stats
+}
+
+// weeklyTotals sums each calendar week's contributions, skipping weeks that
+// haven't started yet as of today (UTC)
+func weeklyTotals(weeks [][]contributionDay, today time.Time) []int {
+	todayStr := today.UTC().Format("2006-01-02")
+
+	var totals []int
+	for _, week := range weeks {
+		started := false
+		total := 0
+		for _, day := range week {
+			if day.Date <= todayStr {
+				started = true
+				total += day.ContributionCount
+			}
+		}
+		if started {
+			totals = append(totals, total)
+		}
+	}
+	return totals
+}
@@ -0,0 +1,110 @@
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStreaks(t *testing.T) {
+	today := time.Date(2026, 1, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		counts      []int // contributions for Jan 1..N
+		wantCurrent int
+		wantLongest int
+		wantRatio   float64
+	}{
+		{
+			name:        "empty calendar",
+			counts:      nil,
+			wantCurrent: 0,
+			wantLongest: 0,
+			wantRatio:   0,
+		},
+		{
+			name:        "active every day",
+			counts:      []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			wantCurrent: 10,
+			wantLongest: 10,
+			wantRatio:   1,
+		},
+		{
+			name:        "empty today keeps current streak",
+			counts:      []int{0, 0, 0, 0, 0, 1, 1, 1, 1, 0},
+			wantCurrent: 4,
+			wantLongest: 4,
+			wantRatio:   0.4,
+		},
+		{
+			name:        "broken streak",
+			counts:      []int{1, 1, 1, 1, 1, 0, 0, 1, 0, 0},
+			wantCurrent: 0,
+			wantLongest: 5,
+			wantRatio:   0.6,
+		},
+		{
+			name:        "future days ignored",
+			counts:      []int{0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 5, 5},
+			wantCurrent: 2,
+			wantLongest: 2,
+			wantRatio:   0.2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var days []contributionDay
+			for i, count := range tt.counts {
+				date := time.Date(2026, 1, i+1, 0, 0, 0, 0, time.UTC)
+				days = append(days, contributionDay{Date: date.Format("2006-01-02"), ContributionCount: count})
+			}
+
+			got := computeStreaks(days, today)
+			if got.Current != tt.wantCurrent {
+				t.Errorf("Current = %d, want %d", got.Current, tt.wantCurrent)
+			}
+			if got.Longest != tt.wantLongest {
+				t.Errorf("Longest = %d, want %d", got.Longest, tt.wantLongest)
+			}
+			if got.ActiveDaysRatio != tt.wantRatio {
+				t.Errorf("ActiveDaysRatio = %v, want %v", got.ActiveDaysRatio, tt.wantRatio)
+			}
+		})
+	}
+}
+
+func TestComputeStreaksUnsortedInput(t *testing.T) {
+	today := time.Date(2026, 1, 3, 12, 0, 0, 0, time.UTC)
+	days := []contributionDay{
+		{Date: "2026-01-03", ContributionCount: 2},
+		{Date: "2026-01-01", ContributionCount: 1},
+		{Date: "2026-01-02", ContributionCount: 1},
+	}
+
+	got := computeStreaks(days, today)
+	if got.Current != 3 || got.Longest != 3 {
+		t.Errorf("got current=%d longest=%d, want 3 and 3", got.Current, got.Longest)
+	}
+}
+
+func TestWeeklyTotals(t *testing.T) {
+	today := time.Date(2026, 1, 8, 12, 0, 0, 0, time.UTC)
+	weeks := [][]contributionDay{
+		{{Date: "2025-12-28", ContributionCount: 9}, {Date: "2026-01-01", ContributionCount: 2}},
+		{{Date: "2026-01-04", ContributionCount: 1}, {Date: "2026-01-08", ContributionCount: 3}, {Date: "2026-01-09", ContributionCount: 7}},
+		{{Date: "2026-01-11", ContributionCount: 5}},
+	}
+
+	// Future days and weeks are excluded
+	got := weeklyTotals(weeks, today)
+	want := []int{11, 4}
+	if len(got) != len(want) {
+		t.Fatalf("weeklyTotals() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("week %d total = %d, want %d", i, got[i], want[i])
+		}
+	}
+}
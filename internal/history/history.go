@@ -0,0 +1,138 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+)
+
+// Snapshot is one week's archived stats
+type Snapshot struct {
+	Week  string // ISO week, e.g. "2026-W06"
+	Path  string
+	Stats *github.Stats
+}
+
+// WeekOf returns the ISO week label (YYYY-Www) for t in UTC
+func WeekOf(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// StatsWeek returns the ISO week stats were fetched in, falling back to now
+// when UpdatedAt is missing or unparseable
+func StatsWeek(stats *github.Stats, now time.Time) string {
+	if t, err := time.Parse(time.RFC3339, stats.UpdatedAt); err == nil {
+		return WeekOf(t)
+	}
+	return WeekOf(now)
+}
+
+// Save archives stats as dir/YYYY-Www.json for the week they were fetched.
+// Earlier weeks are never touched; re-running within a week refreshes only
+// that week's snapshot.
+func Save(dir string, stats *github.Stats, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal stats: %w", err)
+	}
+
+	path := filepath.Join(dir, StatsWeek(stats, now)+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return path, nil
+}
+
+// List returns every snapshot in dir, oldest first.
+// A missing directory is an empty history, not an error.
+func List(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history directory: %w", err)
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		week := strings.TrimSuffix(name, ".json")
+		snap, err := load(filepath.Join(dir, name), week)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snap)
+	}
+
+	// ISO week labels sort chronologically as strings
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Week < snapshots[j].Week })
+	return snapshots, nil
+}
+
+// Load reads the snapshot for a single ISO week
+func Load(dir, week string) (*Snapshot, error) {
+	return load(filepath.Join(dir, week+".json"), week)
+}
+
+// Previous returns the latest snapshot strictly before week, or nil if none
+func Previous(dir, week string) (*Snapshot, error) {
+	snapshots, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if snapshots[i].Week < week {
+			return &snapshots[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Deltas returns per-metric changes from prev to cur, keyed by config metric
+// key. Stats reset every January, so snapshots from different years have no
+// meaningful delta and yield nil.
+func Deltas(prev, cur *github.Stats) map[string]float64 {
+	if prev == nil || cur == nil || prev.Year != cur.Year {
+		return nil
+	}
+
+	before := powerlevel.FromStats(prev)
+	after := powerlevel.FromStats(cur)
+	deltas := make(map[string]float64, len(config.AllMetrics))
+	for _, key := range config.AllMetrics {
+		deltas[key] = after[key] - before[key]
+	}
+	return deltas
+}
+
+func load(path, week string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", week, err)
+	}
+
+	var stats github.Stats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %s: %w", week, err)
+	}
+
+	return &Snapshot{Week: week, Path: path, Stats: &stats}, nil
+}
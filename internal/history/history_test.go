@@ -0,0 +1,139 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+func TestWeekOf(t *testing.T) {
+	tests := []struct {
+		date time.Time
+		want string
+	}{
+		{time.Date(2026, 2, 6, 12, 0, 0, 0, time.UTC), "2026-W06"},
+		{time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), "2026-W53"}, // ISO year differs
+	}
+
+	for _, tt := range tests {
+		if got := WeekOf(tt.date); got != tt.want {
+			t.Errorf("WeekOf(%v) = %s, want %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+func TestSaveAndList(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "history")
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	weeks := []struct {
+		updatedAt string
+		commits   int
+	}{
+		{"2026-02-16T00:00:00Z", 120}, // W08
+		{"2026-02-02T00:00:00Z", 100}, // W06
+		{"2026-02-09T00:00:00Z", 110}, // W07
+	}
+	for _, w := range weeks {
+		stats := &github.Stats{Year: 2026, UpdatedAt: w.updatedAt, Commits: w.commits}
+		if _, err := Save(dir, stats, now); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	snapshots, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(snapshots) != 3 {
+		t.Fatalf("expected 3 snapshots, got %d", len(snapshots))
+	}
+
+	wantWeeks := []string{"2026-W06", "2026-W07", "2026-W08"}
+	for i, want := range wantWeeks {
+		if snapshots[i].Week != want {
+			t.Errorf("snapshot %d week = %s, want %s", i, snapshots[i].Week, want)
+		}
+	}
+	if snapshots[2].Stats.Commits != 120 {
+		t.Errorf("expected latest snapshot commits=120, got %d", snapshots[2].Stats.Commits)
+	}
+}
+
+func TestSaveSameWeekRefreshes(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 2, 6, 0, 0, 0, 0, time.UTC)
+
+	Save(dir, &github.Stats{Year: 2026, Commits: 1}, now)
+	Save(dir, &github.Stats{Year: 2026, Commits: 2}, now)
+
+	snapshots, err := List(dir)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Stats.Commits != 2 {
+		t.Errorf("expected one refreshed snapshot, got %+v", snapshots)
+	}
+}
+
+func TestListMissingDir(t *testing.T) {
+	snapshots, err := List("/nonexistent/history")
+	if err != nil {
+		t.Errorf("expected no error for missing history, got %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Errorf("expected empty history, got %d snapshots", len(snapshots))
+	}
+}
+
+func TestListInvalidSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "2026-W01.json"), []byte("{not json"), 0644)
+
+	if _, err := List(dir); err == nil {
+		t.Error("expected error for corrupt snapshot")
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	Save(dir, &github.Stats{Year: 2026, UpdatedAt: "2026-02-02T00:00:00Z", Commits: 100}, now) // W06
+	Save(dir, &github.Stats{Year: 2026, UpdatedAt: "2026-02-16T00:00:00Z", Commits: 120}, now) // W08
+
+	prev, err := Previous(dir, "2026-W08")
+	if err != nil {
+		t.Fatalf("Previous() error = %v", err)
+	}
+	if prev == nil || prev.Week != "2026-W06" {
+		t.Errorf("expected W06 before W08, got %+v", prev)
+	}
+
+	prev, _ = Previous(dir, "2026-W06")
+	if prev != nil {
+		t.Errorf("expected no snapshot before W06, got %s", prev.Week)
+	}
+}
+
+func TestDeltas(t *testing.T) {
+	prev := &github.Stats{Year: 2026, Commits: 100, StarsReceived: 50, ActiveDaysRatio: 0.5}
+	cur := &github.Stats{Year: 2026, Commits: 142, StarsReceived: 48, ActiveDaysRatio: 0.55}
+
+	deltas := Deltas(prev, cur)
+	if deltas[config.MetricCommits] != 42 {
+		t.Errorf("commits delta = %v, want 42", deltas[config.MetricCommits])
+	}
+	if deltas[config.MetricStars] != -2 {
+		t.Errorf("stars delta = %v, want -2", deltas[config.MetricStars])
+	}
+
+	// Year rollover resets stats, so no deltas
+	cur.Year = 2027
+	if deltas := Deltas(prev, cur); deltas != nil {
+		t.Errorf("expected nil deltas across years, got %v", deltas)
+	}
+}
@@ -0,0 +1,117 @@
+package powerlevel
+
+import (
+	"math"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+// DefaultSoftCapRate is the fraction of gains kept above the soft cap
+const DefaultSoftCapRate = 0.5
+
+// Contribution is one metric's share of the Power Level
+type Contribution struct {
+	Metric string
+	Value  float64 // raw metric value
+	Points float64 // after cap, scaling and weighting
+}
+
+// Result is a computed Power Level with its per-metric breakdown
+type Result struct {
+	Level         int
+	Raw           float64 // weighted sum before soft/pinnacle caps
+	Contributions []Contribution
+}
+
+// DefaultWeights returns the classic formula: the five built-ins count 1:1
+func DefaultWeights() map[string]float64 {
+	return map[string]float64{
+		config.MetricCommits:      1,
+		config.MetricPullRequests: 1,
+		config.MetricIssues:       1,
+		config.MetricReviews:      1,
+		config.MetricStars:        1,
+	}
+}
+
+// Calculate returns the Power Level for the given metric values.
+// values is keyed by config metric key; a nil cfg uses the classic raw sum.
+func Calculate(values map[string]float64, cfg *config.PowerLevelConfig) int {
+	return Compute(values, cfg).Level
+}
+
+// Compute applies caps, optional log scaling and weights per metric, then the
+// soft and pinnacle caps to the total
+func Compute(values map[string]float64, cfg *config.PowerLevelConfig) Result {
+	if cfg == nil {
+		cfg = &config.PowerLevelConfig{}
+	}
+
+	weights := DefaultWeights()
+	for key, weight := range cfg.Weights {
+		weights[key] = weight
+	}
+
+	var result Result
+	for _, key := range config.AllMetrics {
+		weight := weights[key]
+		if weight == 0 {
+			continue
+		}
+
+		value := values[key]
+		scaled := value
+		if limit, ok := cfg.Caps[key]; ok && scaled > limit {
+			scaled = limit
+		}
+		if scaled < 0 {
+			scaled = 0
+		}
+		if cfg.LogScale {
+			scaled = 100 * math.Log10(1+scaled)
+		}
+
+		points := scaled * weight
+		result.Raw += points
+		result.Contributions = append(result.Contributions, Contribution{
+			Metric: key,
+			Value:  value,
+			Points: points,
+		})
+	}
+
+	level := result.Raw
+	if cfg.SoftCap > 0 && level > float64(cfg.SoftCap) {
+		rate := cfg.SoftCapRate
+		if rate == 0 {
+			rate = DefaultSoftCapRate
+		}
+		level = float64(cfg.SoftCap) + (level-float64(cfg.SoftCap))*rate
+	}
+	if cfg.PinnacleCap > 0 && level > float64(cfg.PinnacleCap) {
+		level = float64(cfg.PinnacleCap)
+	}
+
+	result.Level = int(math.Round(level))
+	return result
+}
+
+// FromStats maps fetched GitHub stats to metric values keyed by config metric key
+func FromStats(s *github.Stats) map[string]float64 {
+	return map[string]float64{
+		config.MetricCommits:         float64(s.Commits),
+		config.MetricPullRequests:    float64(s.PullRequests),
+		config.MetricIssues:          float64(s.Issues),
+		config.MetricReviews:         float64(s.Reviews),
+		config.MetricStars:           float64(s.StarsReceived),
+		config.MetricCurrentStreak:   float64(s.CurrentStreak),
+		config.MetricLongestStreak:   float64(s.LongestStreak),
+		config.MetricActiveDaysRatio: s.ActiveDaysRatio,
+		config.MetricMergedPRs:       float64(s.MergedPullRequests),
+		config.MetricReposContrib:    float64(s.RepositoriesContributed),
+		config.MetricFollowers:       float64(s.Followers),
+		config.MetricDiscussions:     float64(s.DiscussionAnswers),
+		config.MetricGists:           float64(s.Gists),
+	}
+}
@@ -0,0 +1,110 @@
+package powerlevel
+
+import (
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+func sampleValues() map[string]float64 {
+	return map[string]float64{
+		config.MetricCommits:      842,
+		config.MetricPullRequests: 156,
+		config.MetricIssues:       89,
+		config.MetricReviews:      234,
+		config.MetricStars:        1247,
+		config.MetricFollowers:    500,
+	}
+}
+
+func TestCalculateDefaultIsRawSum(t *testing.T) {
+	// Followers have no default weight, so only the five built-ins count
+	if got := Calculate(sampleValues(), nil); got != 2568 {
+		t.Errorf("Calculate(nil) = %d, want 2568", got)
+	}
+	if got := Calculate(sampleValues(), &config.PowerLevelConfig{}); got != 2568 {
+		t.Errorf("Calculate(zero config) = %d, want 2568", got)
+	}
+}
+
+func TestCalculateWeightsAndCaps(t *testing.T) {
+	cfg := &config.PowerLevelConfig{
+		Weights: map[string]float64{
+			config.MetricStars:     0.5,
+			config.MetricReviews:   2,
+			config.MetricFollowers: 0.1,
+		},
+		Caps: map[string]float64{
+			config.MetricStars: 500,
+		},
+	}
+
+	// 842 + 156 + 89 + 234*2 + min(1247,500)*0.5 + 500*0.1
+	want := 842 + 156 + 89 + 468 + 250 + 50
+	if got := Calculate(sampleValues(), cfg); got != want {
+		t.Errorf("Calculate() = %d, want %d", got, want)
+	}
+}
+
+func TestCalculateLogScale(t *testing.T) {
+	cfg := &config.PowerLevelConfig{LogScale: true}
+	values := map[string]float64{
+		config.MetricStars:   999, // 100*log10(1000) = 300
+		config.MetricReviews: 99,  // 100*log10(100) = 200
+	}
+
+	if got := Calculate(values, cfg); got != 500 {
+		t.Errorf("Calculate(log) = %d, want 500", got)
+	}
+}
+
+func TestCalculateSoftAndPinnacleCaps(t *testing.T) {
+	values := map[string]float64{config.MetricCommits: 3000}
+
+	tests := []struct {
+		name string
+		cfg  config.PowerLevelConfig
+		want int
+	}{
+		{"soft cap default rate", config.PowerLevelConfig{SoftCap: 2000}, 2500},
+		{"soft cap custom rate", config.PowerLevelConfig{SoftCap: 2000, SoftCapRate: 0.25}, 2250},
+		{"pinnacle cap", config.PowerLevelConfig{PinnacleCap: 1800}, 1800},
+		{"soft then pinnacle", config.PowerLevelConfig{SoftCap: 2000, PinnacleCap: 2400}, 2400},
+		{"below soft cap", config.PowerLevelConfig{SoftCap: 5000}, 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Calculate(values, &tt.cfg); got != tt.want {
+				t.Errorf("Calculate() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeBreakdown(t *testing.T) {
+	result := Compute(sampleValues(), nil)
+
+	if len(result.Contributions) != 5 {
+		t.Fatalf("expected 5 contributions, got %d", len(result.Contributions))
+	}
+	if result.Contributions[0].Metric != config.MetricCommits || result.Contributions[0].Points != 842 {
+		t.Errorf("unexpected first contribution: %+v", result.Contributions[0])
+	}
+	if result.Raw != 2568 {
+		t.Errorf("Raw = %v, want 2568", result.Raw)
+	}
+}
+
+func TestFromStats(t *testing.T) {
+	stats := &github.Stats{Commits: 10, StarsReceived: 20, ActiveDaysRatio: 0.5}
+	values := FromStats(stats)
+
+	if values[config.MetricCommits] != 10 || values[config.MetricStars] != 20 {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if values[config.MetricActiveDaysRatio] != 0.5 {
+		t.Errorf("expected active_days_ratio 0.5, got %v", values[config.MetricActiveDaysRatio])
+	}
+}
@@ -0,0 +1,31 @@
+package powerlevel
+
+import "github.com/castrojo/contribemblem/internal/config"
+
+// DefaultTiers returns Destiny's item rarities as light-level tiers
+func DefaultTiers() []config.TierConfig {
+	return []config.TierConfig{
+		{Name: "Common", Min: 0, Accent: "#C3BCB4", Glow: "#E4E0DA", Icon: config.IconDiamondOutline},
+		{Name: "Uncommon", Min: 500, Accent: "#4A9A5B", Glow: "#7BD18C", Icon: config.IconDiamond},
+		{Name: "Rare", Min: 1000, Accent: "#5076A3", Glow: "#8DB8EE", Icon: config.IconDiamondInset},
+		{Name: "Legendary", Min: 1500, Accent: "#7B4A9B", Glow: "#C39BE3", Icon: config.IconDoubleDiamond},
+		{Name: "Exotic", Min: 2000, Accent: "#CEAE33", Glow: "#F5D96A", Icon: config.IconStar},
+	}
+}
+
+// TierFor returns the highest tier whose Min does not exceed level, and its
+// rank (0 = lowest). Empty tiers use DefaultTiers. Levels below every tier
+// fall into the first one.
+func TierFor(level int, tiers []config.TierConfig) (config.TierConfig, int) {
+	if len(tiers) == 0 {
+		tiers = DefaultTiers()
+	}
+
+	rank := 0
+	for i, tier := range tiers {
+		if level >= tier.Min {
+			rank = i
+		}
+	}
+	return tiers[rank], rank
+}
@@ -0,0 +1,51 @@
+package powerlevel
+
+import (
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+func TestTierForDefaults(t *testing.T) {
+	tests := []struct {
+		level    int
+		wantName string
+		wantRank int
+	}{
+		{0, "Common", 0},
+		{499, "Common", 0},
+		{500, "Uncommon", 1},
+		{1200, "Rare", 2},
+		{1999, "Legendary", 3},
+		{2568, "Exotic", 4},
+	}
+
+	for _, tt := range tests {
+		tier, rank := TierFor(tt.level, nil)
+		if tier.Name != tt.wantName || rank != tt.wantRank {
+			t.Errorf("TierFor(%d) = %s (rank %d), want %s (rank %d)", tt.level, tier.Name, rank, tt.wantName, tt.wantRank)
+		}
+	}
+}
+
+func TestTierForCustom(t *testing.T) {
+	tiers := []config.TierConfig{
+		{Name: "Guardian", Min: 100},
+		{Name: "Paragon", Min: 5000},
+	}
+
+	// Below every threshold falls into the first tier
+	if tier, _ := TierFor(10, tiers); tier.Name != "Guardian" {
+		t.Errorf("expected Guardian below all thresholds, got %s", tier.Name)
+	}
+	if tier, rank := TierFor(6000, tiers); tier.Name != "Paragon" || rank != 1 {
+		t.Errorf("expected Paragon rank 1, got %s rank %d", tier.Name, rank)
+	}
+}
+
+func TestDefaultTiersValid(t *testing.T) {
+	cfg := config.PowerLevelConfig{Tiers: DefaultTiers()}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("default tiers should validate: %v", err)
+	}
+}
@@ -14,7 +14,7 @@ const (
 
 // Inject updates the README between markers with the emblem badge.
 // If markers don't exist, they are appended to the end of the file.
-// badgeImagePath is the relative path to the badge image (e.g., "badge.png").
+// badgeImagePath is the badge image relative to the README (e.g., "badge.png").
 // Returns true if the README content changed, false if unchanged.
 func Inject(readmePath string, badgeImagePath string, updatedAt time.Time) (bool, error) {
 	content, err := os.ReadFile(readmePath)
@@ -0,0 +1,473 @@
+// Package server renders badges on demand over HTTP for any allowed
+// GitHub user, caching fetched stats and emblem artwork for a TTL
+package server
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/badge"
+	"github.com/castrojo/contribemblem/internal/config"
+	"github.com/castrojo/contribemblem/internal/emblem"
+	"github.com/castrojo/contribemblem/internal/github"
+	"github.com/castrojo/contribemblem/internal/powerlevel"
+)
+
+// StatsFunc fetches a user's GitHub stats
+type StatsFunc func(username string) (*github.Stats, error)
+
+// ArtFunc fetches and decodes the artwork of a rotation entry (hash, name
+// or custom artwork)
+type ArtFunc func(ref string) (image.Image, error)
+
+// maxRendered bounds the rendered badge cache; it is cleared when full
+const maxRendered = 256
+
+// User cache bounds, so serve.users: ["*"] can't exhaust memory or the
+// GitHub rate limit
+const (
+	DefaultMaxUsers          = 512
+	DefaultNewUsersPerMinute = 30
+)
+
+// errThrottled rejects a first-time fetch over the NewUsers rate
+var errThrottled = errors.New("too many new users, try again shortly")
+
+// Server serves /badge/{username}.png and .svg
+type Server struct {
+	// Config supplies metrics, the emblem rotation, badge decorations and
+	// serve.users
+	Config *config.Config
+	// Options are the base rendering options that query overrides adjust
+	Options badge.Options
+	Stats   StatsFunc
+	Art     ArtFunc
+	// TTL before stats and emblem selection are refetched (default
+	// config.DefaultServeTTL)
+	TTL time.Duration
+	// Clock defaults to time.Now
+	Clock func() time.Time
+	// MaxUsers bounds how many users' data is cached, evicting the least
+	// recently requested (default DefaultMaxUsers)
+	MaxUsers int
+	// NewUsers limits fetches for users not in the cache, per minute
+	// (default DefaultNewUsersPerMinute)
+	NewUsers int
+
+	mu       sync.Mutex
+	users    map[string]*entry
+	recent   *list.List // user keys, most recently requested first
+	rendered map[string]*renderedBadge
+	// window and newUsers count first-time fetches in the current minute
+	window   time.Time
+	newUsers int
+}
+
+// entry is a user's cached badge data, refreshed under mu
+type entry struct {
+	mu   sync.Mutex
+	data userData
+	elem *list.Element // in Server.recent
+}
+
+// userData is a user's fetched stats and this week's emblem
+type userData struct {
+	stats   *github.Stats
+	ref     string
+	art     image.Image
+	fetched time.Time
+}
+
+// renderedBadge is an encoded badge and the fetch it was rendered from
+type renderedBadge struct {
+	body    []byte
+	etag    string
+	fetched time.Time
+}
+
+// Handler routes badge requests
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /badge/{file}", s.serveBadge)
+	return mux
+}
+
+func (s *Server) serveBadge(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	ext := path.Ext(file)
+	username := strings.TrimSuffix(file, ext)
+	if ext != ".png" && ext != ".svg" {
+		http.Error(w, "badges are .png or .svg", http.StatusNotFound)
+		return
+	}
+	if !config.IsUsername(username) {
+		http.Error(w, "invalid GitHub username", http.StatusBadRequest)
+		return
+	}
+	if !s.allowed(username) {
+		http.Error(w, "user not served here", http.StatusNotFound)
+		return
+	}
+
+	v, err := s.parseView(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, stale, err := s.lookup(username)
+	if errors.Is(err, errThrottled) {
+		w.Header().Set("Retry-After", "60")
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: badge for %s: %v\n", username, err)
+		http.Error(w, "failed to fetch stats or emblem", http.StatusBadGateway)
+		return
+	}
+
+	key := username + ext + "?" + v.key
+	s.mu.Lock()
+	cached := s.rendered[key]
+	s.mu.Unlock()
+	if cached == nil || !cached.fetched.Equal(data.fetched) {
+		body, err := s.render(r, username, ext, data, v)
+		if err != nil {
+			if r.Context().Err() == nil {
+				fmt.Fprintf(os.Stderr, "Warning: badge for %s: %v\n", username, err)
+			}
+			http.Error(w, "failed to render badge", http.StatusInternalServerError)
+			return
+		}
+		sum := sha256.Sum256(body)
+		cached = &renderedBadge{body: body, etag: `"` + hex.EncodeToString(sum[:8]) + `"`, fetched: data.fetched}
+		s.mu.Lock()
+		if len(s.rendered) >= maxRendered {
+			clear(s.rendered)
+		}
+		s.rendered[key] = cached
+		s.mu.Unlock()
+	}
+
+	h := w.Header()
+	if ext == ".svg" {
+		h.Set("Content-Type", "image/svg+xml")
+	} else {
+		h.Set("Content-Type", "image/png")
+	}
+	h.Set("ETag", cached.etag)
+	h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(s.ttl().Seconds())))
+	if stale {
+		h.Set("Warning", `110 - "Response is Stale"`)
+	}
+	http.ServeContent(w, r, file, data.fetched, bytes.NewReader(cached.body))
+}
+
+// allowed reports whether serve.users admits username; an empty list
+// admits only the configured username
+func (s *Server) allowed(username string) bool {
+	users := s.Config.Serve.Users
+	if len(users) == 0 {
+		users = []string{s.Config.Username}
+	}
+	for _, u := range users {
+		if u == config.AnyUser || strings.EqualFold(u, username) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup returns username's cached data, refetching it once the TTL has
+// passed. A failed refetch keeps serving the previous data, reported stale.
+func (s *Server) lookup(username string) (data userData, stale bool, err error) {
+	key := strings.ToLower(username)
+	e, err := s.entry(key)
+	if err != nil {
+		return userData{}, false, err
+	}
+
+	// Concurrent requests for the same user wait for a single fetch
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	now := s.now()
+	if e.data.stats != nil && now.Sub(e.data.fetched) < s.ttl() {
+		return e.data, false, nil
+	}
+
+	fresh, err := s.refresh(e.data, username, now)
+	if err != nil {
+		if e.data.stats == nil {
+			s.forget(key, e)
+			return userData{}, false, err
+		}
+		fmt.Fprintf(os.Stderr, "Warning: serving stale badge for %s: %v\n", username, err)
+		return e.data, true, nil
+	}
+	e.data = fresh
+	return fresh, false, nil
+}
+
+// entry returns the cache entry for a user key, marking it most recently
+// requested. New entries are rate limited and evict the least recently
+// requested user once MaxUsers are cached.
+func (s *Server) entry(key string) (*entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.users == nil {
+		s.users = make(map[string]*entry)
+		s.recent = list.New()
+		s.rendered = make(map[string]*renderedBadge)
+	}
+	if e := s.users[key]; e != nil {
+		s.recent.MoveToFront(e.elem)
+		return e, nil
+	}
+
+	now := s.now()
+	if now.Sub(s.window) >= time.Minute {
+		s.window, s.newUsers = now, 0
+	}
+	if s.newUsers >= orDefault(s.NewUsers, DefaultNewUsersPerMinute) {
+		return nil, errThrottled
+	}
+	s.newUsers++
+
+	for len(s.users) >= orDefault(s.MaxUsers, DefaultMaxUsers) {
+		oldest := s.recent.Back()
+		delete(s.users, oldest.Value.(string))
+		s.recent.Remove(oldest)
+	}
+	e := &entry{}
+	e.elem = s.recent.PushFront(key)
+	s.users[key] = e
+	return e, nil
+}
+
+// forget drops a user whose first fetch failed, so it doesn't hold a slot
+func (s *Server) forget(key string, e *entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.users[key] == e {
+		delete(s.users, key)
+		s.recent.Remove(e.elem)
+	}
+}
+
+// refresh fetches stats and selects this week's emblem for the user,
+// reusing the artwork when the selection hasn't changed
+func (s *Server) refresh(prev userData, username string, now time.Time) (userData, error) {
+	stats, err := s.Stats(username)
+	if err != nil {
+		return prev, fmt.Errorf("failed to fetch stats: %w", err)
+	}
+
+	selector := &emblem.Selector{
+		Config:   &s.Config.Emblems,
+		Username: username,
+		Clock:    s.now,
+		// Achievement unlocks need the owner's ledger, so only Power Level
+		// gates other users' emblems
+		Stats: &emblem.Stats{PowerLevel: powerlevel.Calculate(powerlevel.FromStats(stats), &s.Config.PowerLevel)},
+	}
+	ref, err := selector.Select()
+	if err != nil {
+		return prev, fmt.Errorf("failed to select emblem: %w", err)
+	}
+
+	art := prev.art
+	if ref != prev.ref || art == nil {
+		if art, err = s.Art(ref); err != nil {
+			return prev, fmt.Errorf("failed to fetch emblem %s: %w", ref, err)
+		}
+	}
+	return userData{stats: stats, ref: ref, art: art, fetched: now}, nil
+}
+
+// render draws the badge and encodes it as PNG, wrapped in SVG for .svg
+func (s *Server) render(r *http.Request, username, ext string, data userData, v view) ([]byte, error) {
+	opts := v.opts
+	stats := s.badgeStats(username, data.stats)
+	if v.metrics != nil {
+		stats.Metrics = v.metrics
+	}
+	// A crop override replaces the emblem's configured offset
+	if offset, ok := s.Config.Emblems.CropOffsets[data.ref]; ok && !v.crop {
+		opts.CropOffset = &offset
+	}
+
+	img, err := badge.Render(r.Context(), data.art, *stats, opts)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := badge.Encode(&buf, img, badge.FormatPNG, opts.Encoding); err != nil {
+		return nil, err
+	}
+	if ext != ".svg" {
+		return buf.Bytes(), nil
+	}
+
+	level := powerlevel.Calculate(powerlevel.FromStats(data.stats), opts.PowerLevel)
+	return svgBadge(buf.Bytes(), fmt.Sprintf("%s · Power Level %s", username, badge.FormatNumber(level))), nil
+}
+
+// badgeStats builds the badge for another user from config. Deltas,
+// history sparklines and triumphs come from the owner's data directory,
+// so served badges use the contribution calendar and leave the rest off.
+func (s *Server) badgeStats(username string, stats *github.Stats) *badge.Stats {
+	cfg := s.Config
+	badgeStats := badge.FromGitHub(username, stats)
+	badgeStats.Metrics = cfg.Metrics.Enabled()
+	if cfg.Badge.LanguageBar {
+		badgeStats.Languages = badge.TopLanguages(stats.Languages, cfg.Badge.TopLanguages)
+	}
+	if cfg.Badge.Sparkline.Enabled {
+		weeks := cfg.Badge.Sparkline.Weeks
+		if weeks == 0 {
+			weeks = config.DefaultSparklineWeeks
+		}
+		counts := stats.WeeklyContributions
+		if len(counts) > weeks {
+			counts = counts[len(counts)-weeks:]
+		}
+		for _, count := range counts {
+			badgeStats.Sparkline = append(badgeStats.Sparkline, float64(count))
+		}
+	}
+	return badgeStats
+}
+
+// Query override values
+const (
+	ThemeClassic  = "classic" // gold accent
+	ThemeTiers    = "tiers"   // styled by Power Level tier
+	ThemePalette  = "palette" // accent sampled from the emblem art
+	ContrastFixed = "fixed"
+	ContrastAuto  = "adaptive"
+)
+
+// view is a badge request's query overrides
+type view struct {
+	opts    badge.Options
+	metrics []string // nil keeps the configured metrics
+	crop    bool     // crop strategy overridden
+	key     string   // canonical query, for the render cache
+}
+
+// parseView applies ?theme=, ?crop=, ?metrics= and ?contrast= to the base
+// options
+func (s *Server) parseView(q url.Values) (view, error) {
+	opts := s.Options
+	opts.Animation = nil
+	v := view{}
+	canonical := url.Values{}
+
+	if theme := q.Get("theme"); theme != "" {
+		switch theme {
+		case ThemeClassic:
+			opts.Tiers, opts.Palette = nil, false
+		case ThemeTiers:
+			opts.Tiers, opts.Palette = s.Config.PowerLevel.Tiers, false
+			if len(opts.Tiers) == 0 {
+				opts.Tiers = powerlevel.DefaultTiers()
+			}
+		case ThemePalette:
+			opts.Tiers, opts.Palette = nil, true
+		default:
+			return v, fmt.Errorf("theme %q is not one of %v", theme, []string{ThemeClassic, ThemeTiers, ThemePalette})
+		}
+		canonical.Set("theme", theme)
+	}
+
+	if crop := q.Get("crop"); crop != "" {
+		if !slices.Contains(config.CropStrategies, crop) {
+			return v, fmt.Errorf("crop %q is not one of %v", crop, config.CropStrategies)
+		}
+		opts.Crop, v.crop = crop, true
+		canonical.Set("crop", crop)
+	}
+
+	if contrast := q.Get("contrast"); contrast != "" {
+		switch contrast {
+		case ContrastFixed:
+			opts.ContrastTarget = 0
+		case ContrastAuto:
+			opts.ContrastTarget = s.Config.Badge.ContrastTarget
+			if opts.ContrastTarget == 0 {
+				opts.ContrastTarget = badge.DefaultContrastTarget
+			}
+		default:
+			return v, fmt.Errorf("contrast must be %q or %q", ContrastFixed, ContrastAuto)
+		}
+		canonical.Set("contrast", contrast)
+	}
+
+	if list := q.Get("metrics"); list != "" {
+		metrics := strings.Split(list, ",")
+		if len(metrics) > config.MaxMetrics {
+			return v, fmt.Errorf("at most %d metrics fit on the badge", config.MaxMetrics)
+		}
+		for _, m := range metrics {
+			if !slices.Contains(config.AllMetrics, m) {
+				return v, fmt.Errorf("metric %q is not one of %v", m, config.AllMetrics)
+			}
+		}
+		v.metrics = metrics
+		canonical.Set("metrics", list)
+	}
+
+	v.opts, v.key = opts, canonical.Encode()
+	return v, nil
+}
+
+// svgBadge wraps PNG bytes in an SVG so it can be embedded where only SVG
+// is accepted, with title as its accessible name
+func svgBadge(png []byte, title string) []byte {
+	title = html.EscapeString(title)
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`,
+		badge.Width, badge.Height, badge.Width, badge.Height, title)
+	fmt.Fprintf(&b, "<title>%s</title>", title)
+	fmt.Fprintf(&b, `<image width="%d" height="%d" href="data:image/png;base64,%s"/>`,
+		badge.Width, badge.Height, base64.StdEncoding.EncodeToString(png))
+	b.WriteString("</svg>\n")
+	return b.Bytes()
+}
+
+func (s *Server) ttl() time.Duration {
+	if s.TTL <= 0 {
+		return config.DefaultServeTTL
+	}
+	return s.TTL
+}
+
+func orDefault(n, def int) int {
+	if n <= 0 {
+		return def
+	}
+	return n
+}
+
+func (s *Server) now() time.Time {
+	if s.Clock != nil {
+		return s.Clock()
+	}
+	return time.Now()
+}
@@ -0,0 +1,377 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/castrojo/contribemblem/internal/badge"
+	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+const testManifest = `{
+  "4052831236": {
+    "displayProperties": {"name": "Activate ESCALATION", "icon": "/icons/a_icon.jpg"},
+    "secondarySpecial": "/icons/a_special.png",
+    "itemType": 14
+  }
+}`
+
+// hostTransport sends each request to the stand-in server for its host
+type hostTransport map[string]*httptest.Server
+
+func (h hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	srv, ok := h[req.URL.Host]
+	if !ok {
+		return nil, fmt.Errorf("unexpected request to %s", req.URL)
+	}
+	req.URL.Scheme = "http"
+	req.URL.Host = strings.TrimPrefix(srv.URL, "http://")
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// standIns fakes the GitHub GraphQL API and bungie.net
+type standIns struct {
+	githubHits atomic.Int32
+	artHits    atomic.Int32
+	failGitHub atomic.Bool
+	client     *http.Client
+}
+
+func newStandIns(t *testing.T) *standIns {
+	t.Helper()
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	s := &standIns{}
+
+	gh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.githubHits.Add(1)
+		if s.failGitHub.Load() {
+			http.Error(w, "unavailable", http.StatusInternalServerError)
+			return
+		}
+		var query struct {
+			Variables struct {
+				Username string `json:"username"`
+			} `json:"variables"`
+		}
+		json.NewDecoder(r.Body).Decode(&query)
+		fmt.Fprintf(w, `{"data": {"user": {
+			"contributionsCollection": {
+				"totalCommitContributions": %d,
+				"totalPullRequestContributions": 15,
+				"totalIssueContributions": 8,
+				"totalPullRequestReviewContributions": 23
+			},
+			"repositories": {"nodes": [{"stargazerCount": 100}]}
+		}}}`, 10*len(query.Variables.Username))
+	}))
+	t.Cleanup(gh.Close)
+
+	art := testArt(t)
+	bungieNet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.artHits.Add(1)
+		if r.URL.Path != "/icons/a_special.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(art)
+	}))
+	t.Cleanup(bungieNet.Close)
+
+	s.client = &http.Client{Transport: hostTransport{
+		"api.github.com": gh,
+		"www.bungie.net": bungieNet,
+	}}
+	return s
+}
+
+// testArt encodes a gradient emblem wider than the badge, so crop
+// strategies differ
+func testArt(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 1600, 192))
+	for y := 0; y < 192; y++ {
+		for x := 0; x < 1600; x++ {
+			img.Set(x, y, color.RGBA{uint8(x / 7), 40, uint8(y), 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// testServer wires a Server to the stand-ins with a movable clock
+func testServer(t *testing.T, s *standIns) (*Server, *time.Time) {
+	t.Helper()
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "manifest.json")
+	if err := os.WriteFile(manifest, []byte(testManifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	items, err := bungie.OpenManifest(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.DefaultConfig()
+	cfg.Username = "castrojo"
+	cfg.Emblems.Rotation = []string{"4052831236"}
+	cfg.Emblems.Fallback = "4052831236"
+	cfg.Serve.Users = []string{"castrojo", "jeefy"}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	srv := &Server{
+		Config: cfg,
+		Stats:  GitHubStats(s.client),
+		Art:    BungieArt(items, filepath.Join(dir, "art"), s.client),
+		TTL:    time.Hour,
+		Clock:  func() time.Time { return now },
+	}
+	return srv, &now
+}
+
+func get(t *testing.T, h http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("GET", target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeBadgePNG(t *testing.T) {
+	stand := newStandIns(t)
+	srv, _ := testServer(t, stand)
+	h := srv.Handler()
+
+	rec := get(t, h, "/badge/castrojo.png", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	img, err := png.Decode(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("decoding badge: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != badge.Width || b.Dy() != badge.Height {
+		t.Errorf("badge is %dx%d", b.Dx(), b.Dy())
+	}
+
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Last-Modified") != "Sun, 18 Oct 2026 12:00:00 GMT" {
+		t.Fatalf("missing validators: ETag %q, Last-Modified %q", etag, rec.Header().Get("Last-Modified"))
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=3600" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+
+	rec = get(t, h, "/badge/castrojo.png", http.Header{"If-None-Match": {etag}})
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("conditional request status = %d, want 304", rec.Code)
+	}
+}
+
+func TestServeBadgeTTL(t *testing.T) {
+	stand := newStandIns(t)
+	srv, now := testServer(t, stand)
+	h := srv.Handler()
+
+	first := get(t, h, "/badge/castrojo.png", nil)
+	get(t, h, "/badge/castrojo.png?theme=palette", nil)
+	get(t, h, "/badge/castrojo.svg", nil)
+	if n := stand.githubHits.Load(); n != 1 {
+		t.Errorf("GitHub fetched %d times within the TTL, want 1", n)
+	}
+
+	*now = now.Add(2 * time.Hour)
+	second := get(t, h, "/badge/castrojo.png", nil)
+	if n := stand.githubHits.Load(); n != 2 {
+		t.Errorf("GitHub fetched %d times after the TTL, want 2", n)
+	}
+	if stand.artHits.Load() != 1 {
+		t.Errorf("emblem art downloaded %d times, want once", stand.artHits.Load())
+	}
+	if first.Header().Get("Last-Modified") == second.Header().Get("Last-Modified") {
+		t.Error("Last-Modified should advance with the refetch")
+	}
+}
+
+func TestServeBadgeStale(t *testing.T) {
+	stand := newStandIns(t)
+	srv, now := testServer(t, stand)
+	h := srv.Handler()
+
+	stand.failGitHub.Store(true)
+	if rec := get(t, h, "/badge/castrojo.png", nil); rec.Code != http.StatusBadGateway {
+		t.Errorf("status with nothing cached = %d, want 502", rec.Code)
+	}
+
+	stand.failGitHub.Store(false)
+	fresh := get(t, h, "/badge/castrojo.png", nil)
+	if fresh.Code != http.StatusOK {
+		t.Fatalf("status = %d", fresh.Code)
+	}
+
+	stand.failGitHub.Store(true)
+	*now = now.Add(2 * time.Hour)
+	stale := get(t, h, "/badge/castrojo.png", nil)
+	if stale.Code != http.StatusOK || !bytes.Equal(stale.Body.Bytes(), fresh.Body.Bytes()) {
+		t.Errorf("failed refresh should serve the cached badge, got %d", stale.Code)
+	}
+	if stale.Header().Get("Warning") == "" {
+		t.Error("stale badge should carry a Warning header")
+	}
+}
+
+func TestServeBadgeSVG(t *testing.T) {
+	stand := newStandIns(t)
+	srv, _ := testServer(t, stand)
+
+	rec := get(t, srv.Handler(), "/badge/jeefy.svg", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/svg+xml" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"<svg", "<title>jeefy · Power Level", "data:image/png;base64,"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("SVG missing %q", want)
+		}
+	}
+}
+
+func TestServeBadgeOverrides(t *testing.T) {
+	stand := newStandIns(t)
+	srv, _ := testServer(t, stand)
+	h := srv.Handler()
+
+	base := get(t, h, "/badge/castrojo.png", nil)
+	for _, query := range []string{"theme=tiers", "crop=left", "metrics=commits,stars", "contrast=adaptive"} {
+		rec := get(t, h, "/badge/castrojo.png?"+query, nil)
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d: %s", query, rec.Code, rec.Body)
+			continue
+		}
+		if bytes.Equal(rec.Body.Bytes(), base.Body.Bytes()) {
+			t.Errorf("%s: badge unchanged", query)
+		}
+		if rec.Header().Get("ETag") == base.Header().Get("ETag") {
+			t.Errorf("%s: ETag unchanged", query)
+		}
+	}
+}
+
+func TestServeBadgeErrors(t *testing.T) {
+	stand := newStandIns(t)
+	srv, _ := testServer(t, stand)
+	h := srv.Handler()
+
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/badge/castrojo.png?theme=neon", http.StatusBadRequest},
+		{"/badge/castrojo.png?crop=top", http.StatusBadRequest},
+		{"/badge/castrojo.png?metrics=karma", http.StatusBadRequest},
+		{"/badge/castrojo.png?contrast=high", http.StatusBadRequest},
+		{"/badge/-bad-.png", http.StatusBadRequest},
+		{"/badge/mrbobbytables.png", http.StatusNotFound},
+		{"/badge/castrojo.gif", http.StatusNotFound},
+		{"/badge/castrojo", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := get(t, h, tt.target, nil); rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+	if n := stand.githubHits.Load(); n != 0 {
+		t.Errorf("rejected requests fetched GitHub %d times", n)
+	}
+}
+
+func TestServeAnyUser(t *testing.T) {
+	stand := newStandIns(t)
+	srv, _ := testServer(t, stand)
+	srv.Config.Serve.Users = []string{config.AnyUser}
+
+	rec := get(t, srv.Handler(), "/badge/mrbobbytables.png", nil)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 for any user", rec.Code)
+	}
+
+	srv.Config.Serve.Users = nil
+	if rec := get(t, srv.Handler(), "/badge/jeefy.png", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("without serve.users only the configured user is served, got %d", rec.Code)
+	}
+}
+
+func TestServeUserCacheBounded(t *testing.T) {
+	stand := newStandIns(t)
+	srv, _ := testServer(t, stand)
+	srv.Config.Serve.Users = []string{config.AnyUser}
+	srv.MaxUsers = 2
+	h := srv.Handler()
+
+	for _, user := range []string{"castrojo", "jeefy", "castrojo", "mrbobbytables"} {
+		if rec := get(t, h, "/badge/"+user+".png", nil); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200", user, rec.Code)
+		}
+	}
+	if len(srv.users) != 2 {
+		t.Errorf("cached %d users, want 2", len(srv.users))
+	}
+	hits := stand.githubHits.Load()
+
+	// castrojo was requested more recently than jeefy, so jeefy went first
+	get(t, h, "/badge/castrojo.png", nil)
+	if got := stand.githubHits.Load(); got != hits {
+		t.Errorf("castrojo refetched after eviction of another user")
+	}
+	get(t, h, "/badge/jeefy.png", nil)
+	if got := stand.githubHits.Load(); got != hits+1 {
+		t.Errorf("GitHub hits = %d, want %d after jeefy was evicted", got, hits+1)
+	}
+}
+
+func TestServeNewUserThrottle(t *testing.T) {
+	stand := newStandIns(t)
+	srv, now := testServer(t, stand)
+	srv.Config.Serve.Users = []string{config.AnyUser}
+	srv.NewUsers = 2
+	h := srv.Handler()
+
+	get(t, h, "/badge/castrojo.png", nil)
+	get(t, h, "/badge/jeefy.png", nil)
+	rec := get(t, h, "/badge/mrbobbytables.png", nil)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("third new user: status = %d, Retry-After %q, want 429 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := get(t, h, "/badge/castrojo.png", nil); rec.Code != http.StatusOK {
+		t.Errorf("cached user: status = %d, want 200 while throttled", rec.Code)
+	}
+
+	*now = now.Add(time.Minute)
+	if rec := get(t, h, "/badge/mrbobbytables.png", nil); rec.Code != http.StatusOK {
+		t.Errorf("after a minute: status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/castrojo/contribemblem/internal/badge"
+	"github.com/castrojo/contribemblem/internal/bungie"
+	"github.com/castrojo/contribemblem/internal/emblem"
+	"github.com/castrojo/contribemblem/internal/github"
+)
+
+// GitHubStats fetches stats from the GitHub GraphQL API (GITHUB_TOKEN
+// required). A nil client uses github.FetchStats' default.
+func GitHubStats(client *http.Client) StatsFunc {
+	return func(username string) (*github.Stats, error) {
+		return github.FetchStats(username, client)
+	}
+}
+
+// BungieArt resolves rotation entries against manifest, parsed once at
+// startup, and downloads their artwork once into dir. Custom artwork is
+// read or downloaded as configured. A nil manifest serves custom artwork
+// only; a nil client uses a 60s timeout.
+func BungieArt(manifest *bungie.Manifest, dir string, client *http.Client) ArtFunc {
+	return func(ref string) (image.Image, error) {
+		if emblem.IsCustom(ref) {
+			sum := sha256.Sum256([]byte(ref))
+			path := filepath.Join(dir, "custom-"+hex.EncodeToString(sum[:8]))
+			if err := emblem.FetchCustom(ref, path); err != nil {
+				return nil, err
+			}
+			return badge.LoadEmblem(path)
+		}
+
+		if manifest == nil {
+			return nil, fmt.Errorf("emblem %s: no Bungie manifest loaded", ref)
+		}
+		e, err := manifest.Resolve(ref)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, e.Hash+".jpg")
+		if _, err := os.Stat(path); err != nil {
+			if err := bungie.DownloadEmblemArtWith(client, e, path); err != nil {
+				return nil, err
+			}
+		}
+		return badge.LoadEmblem(path)
+	}
+}
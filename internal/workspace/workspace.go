@@ -0,0 +1,86 @@
+// Package workspace locates the files commands read and write. Paths are
+// relative to the working directory (see --workdir) unless configured as
+// absolute.
+package workspace
+
+import (
+	"path/filepath"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+// Workspace holds the configured output locations
+type Workspace struct {
+	// DataDir holds fetched and derived data (output.data_dir)
+	DataDir string
+	// Badge is the static badge image (output.badge)
+	Badge string
+	// Animation is the animated badge (badge.animation.output, otherwise
+	// beside Badge)
+	Animation string
+	// Readme is the README the badge is injected into (readme.path)
+	Readme string
+}
+
+// New resolves the workspace from config (defaults when cfg is nil)
+func New(cfg *config.Config) *Workspace {
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	w := &Workspace{
+		DataDir:   cfg.Output.DataDirectory(),
+		Badge:     cfg.Output.BadgePath(),
+		Animation: cfg.Badge.Animation.OutputPath(),
+		Readme:    cfg.Readme.ReadmePath(),
+	}
+	if cfg.Badge.Animation.Output == "" {
+		w.Animation = filepath.Join(filepath.Dir(w.Badge), w.Animation)
+	}
+	return w
+}
+
+// Stats is the cached GitHub stats (plus stats.meta.json beside it)
+func (w *Workspace) Stats() string { return w.data("stats.json") }
+
+// Emblem is this week's downloaded emblem artwork
+func (w *Workspace) Emblem() string { return w.data("emblem.jpg") }
+
+// EmblemRef records which emblem entry Emblem was fetched for
+func (w *Workspace) EmblemRef() string { return w.data("emblem.ref") }
+
+// Manifest is the cached Bungie item definitions
+func (w *Workspace) Manifest() string { return w.data("manifest.json") }
+
+// EmblemConfig is the legacy JSON rotation used without contribemblem.yml
+func (w *Workspace) EmblemConfig() string { return w.data("emblem-config.json") }
+
+// History is the directory of weekly stats snapshots
+func (w *Workspace) History() string { return w.data("history") }
+
+// Ledger is the achievements ledger
+func (w *Workspace) Ledger() string { return w.data("achievements.json") }
+
+// Preview is the default output directory of emblems preview
+func (w *Workspace) Preview() string { return w.data("preview") }
+
+// ServeArt caches emblem artwork downloaded by the badge server
+func (w *Workspace) ServeArt() string { return w.data("serve") }
+
+// BadgeLink is the badge as the README links to it: relative to the
+// README's directory, with forward slashes
+func (w *Workspace) BadgeLink() string {
+	badge, errBadge := filepath.Abs(w.Badge)
+	readmeDir, errReadme := filepath.Abs(filepath.Dir(w.Readme))
+	if errBadge != nil || errReadme != nil {
+		return filepath.ToSlash(w.Badge)
+	}
+	rel, err := filepath.Rel(readmeDir, badge)
+	if err != nil {
+		return filepath.ToSlash(w.Badge)
+	}
+	return filepath.ToSlash(rel)
+}
+
+func (w *Workspace) data(name string) string {
+	return filepath.Join(w.DataDir, name)
+}
@@ -0,0 +1,58 @@
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/castrojo/contribemblem/internal/config"
+)
+
+func TestNewDefaults(t *testing.T) {
+	for name, cfg := range map[string]*config.Config{"nil": nil, "empty": {}} {
+		t.Run(name, func(t *testing.T) {
+			w := New(cfg)
+			if w.Stats() != filepath.Join("data", "stats.json") {
+				t.Errorf("Stats() = %q", w.Stats())
+			}
+			if w.Emblem() != filepath.Join("data", "emblem.jpg") || w.History() != filepath.Join("data", "history") {
+				t.Errorf("Emblem() = %q, History() = %q", w.Emblem(), w.History())
+			}
+			if w.Badge != "badge.png" || w.Readme != "README.md" || w.BadgeLink() != "badge.png" {
+				t.Errorf("Badge = %q, Readme = %q, BadgeLink() = %q", w.Badge, w.Readme, w.BadgeLink())
+			}
+		})
+	}
+}
+
+func TestNewConfigured(t *testing.T) {
+	cfg := &config.Config{
+		Output: config.OutputConfig{Badge: "assets/badge.png", DataDir: ".contribemblem"},
+		Readme: config.ReadmeConfig{Path: "profile/README.md"},
+	}
+	w := New(cfg)
+
+	if w.Manifest() != filepath.Join(".contribemblem", "manifest.json") {
+		t.Errorf("Manifest() = %q", w.Manifest())
+	}
+	if w.Ledger() != filepath.Join(".contribemblem", "achievements.json") {
+		t.Errorf("Ledger() = %q", w.Ledger())
+	}
+	if got := w.BadgeLink(); got != "../assets/badge.png" {
+		t.Errorf("BadgeLink() = %q, want ../assets/badge.png", got)
+	}
+	if w.Animation != filepath.Join("assets", "badge.gif") {
+		t.Errorf("Animation = %q, want it beside the badge", w.Animation)
+	}
+
+	cfg.Badge.Animation.Output = "anim.gif"
+	if got := New(cfg).Animation; got != "anim.gif" {
+		t.Errorf("Animation = %q, want the configured anim.gif", got)
+	}
+}
+
+func TestBadgeLinkSameDirectory(t *testing.T) {
+	w := &Workspace{Badge: "profile/badge.png", Readme: "profile/README.md"}
+	if got := w.BadgeLink(); got != "badge.png" {
+		t.Errorf("BadgeLink() = %q, want badge.png", got)
+	}
+}